@@ -0,0 +1,141 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+)
+
+// AggBuilder builds an aggregate function call, such as
+// array_agg(DISTINCT name ORDER BY name) FILTER (WHERE active).
+//
+// It can be used as a column, in HAVING with Expr, or as the function of Over.
+//
+// Ex:
+//
+//	Agg("array_agg", "name").Distinct().OrderBy("name").Filter(Eq{"active": true})
+type AggBuilder struct {
+	name     string
+	args     []SQLizer
+	distinct bool
+	orderBy  []SQLizer
+	filter   []SQLizer
+}
+
+// Agg returns a new AggBuilder calling the aggregate function name with args.
+// Each arg can be a string, such as a column name or "*", or a SQLizer, like
+// in OrderBy. Use Expr("?", v) to bind a value as an arg.
+//
+// Ex:
+//
+//	Agg("count", "*")
+//	Agg("string_agg", "name", Expr("?", ", "))
+func Agg(name string, args ...any) AggBuilder {
+	a := AggBuilder{name: name}
+	for _, arg := range args {
+		a.args = append(a.args, newPart(arg))
+	}
+	return a
+}
+
+// SQL returns the aggregate function call.
+func (a AggBuilder) SQL() (string, []any, error) {
+	return resolvedSQL(a)
+}
+
+func (a AggBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if a.name == "" {
+		err = errors.New("aggregate functions must have a name")
+		return
+	}
+	if a.distinct {
+		if err = a.validateDistinct(); err != nil {
+			return
+		}
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString(a.name)
+	sql.WriteString("(")
+	if a.distinct {
+		sql.WriteString("DISTINCT ")
+	}
+	args, err = appendSQL(a.args, sql, ", ", args)
+	if err != nil {
+		return
+	}
+
+	if len(a.orderBy) > 0 {
+		sql.WriteString(" ORDER BY ")
+		args, err = appendSQL(a.orderBy, sql, ", ", args)
+		if err != nil {
+			return
+		}
+	}
+	sql.WriteString(")")
+
+	if len(a.filter) > 0 {
+		sql.WriteString(" FILTER (WHERE ")
+		args, err = appendSQL(a.filter, sql, " AND ", args)
+		if err != nil {
+			return
+		}
+		sql.WriteString(")")
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// validateDistinct checks that the ORDER BY expressions of a DISTINCT
+// aggregate are in its argument list, as PostgreSQL requires.
+func (a AggBuilder) validateDistinct() error {
+	if len(a.args) == 0 {
+		return errors.New("DISTINCT aggregates must have an argument")
+	}
+	args, err := orderItems(a.args)
+	if err != nil {
+		return err
+	}
+	orderBy, err := orderItems(a.orderBy)
+	if err != nil {
+		return err
+	}
+	for _, o := range orderBy {
+		found := false
+		for _, arg := range args {
+			if o.equal(arg) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("in an aggregate with DISTINCT, ORDER BY expressions must appear in argument list")
+		}
+	}
+	return nil
+}
+
+// Distinct aggregates only the distinct values of the args.
+func (a AggBuilder) Distinct() AggBuilder {
+	a.distinct = true
+	return a
+}
+
+// OrderBy adds ORDER BY expressions to the aggregate, for aggregates whose
+// result depends on the order of the input, such as array_agg or string_agg.
+// Each expression can be a string or a SQLizer.
+func (a AggBuilder) OrderBy(exprs ...any) AggBuilder {
+	for _, expr := range exprs {
+		a.orderBy = append(a.orderBy, newPart(expr))
+	}
+	return a
+}
+
+// Filter adds an expression to the FILTER clause of the aggregate, so only
+// the matching rows are aggregated. Multiple calls are joined with AND.
+//
+// See SelectBuilder.Where.
+func (a AggBuilder) Filter(pred any, args ...any) AggBuilder {
+	a.filter = append(a.filter, newWherePart(pred, args...))
+	return a
+}
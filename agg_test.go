@@ -0,0 +1,145 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAgg(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "count",
+			b:       Agg("count", "*"),
+			wantSQL: "count(*)",
+		},
+		{
+			name:    "no_args",
+			b:       Agg("rank"),
+			wantSQL: "rank()",
+		},
+		{
+			name:    "order_by",
+			b:       Agg("array_agg", "name").OrderBy("name"),
+			wantSQL: "array_agg(name ORDER BY name)",
+		},
+		{
+			name:     "args",
+			b:        Agg("string_agg", "name", Expr("?", ", ")).OrderBy("name DESC", "id"),
+			wantSQL:  "string_agg(name, ? ORDER BY name DESC, id)",
+			wantArgs: []any{", "},
+		},
+		{
+			name:     "distinct_order_filter",
+			b:        Agg("array_agg", "name").Distinct().OrderBy("name").Filter(Eq{"active": true}),
+			wantSQL:  "array_agg(DISTINCT name ORDER BY name) FILTER (WHERE active = ?)",
+			wantArgs: []any{true},
+		},
+		{
+			name:     "filters",
+			b:        Agg("sum", "amount").Filter(Or{Eq{"kind": "sale"}, Lt{"amount": 0}}).Filter("region = ?", "eu"),
+			wantSQL:  "sum(amount) FILTER (WHERE (kind = ? OR amount < ?) AND region = ?)",
+			wantArgs: []any{"sale", 0, "eu"},
+		},
+		{
+			name: "select",
+			b: Select("department").
+				Column(Agg("count", "*").Filter(Eq{"active": true})).
+				Column(Alias{Expr: Agg("array_agg", "name").OrderBy("hired_at"), As: "names"}).
+				From("employees").
+				Where("company_id = ?", 1).
+				GroupBy("department").
+				Having(Expr("? > ?", Agg("count", "*").Filter("salary > ?", 1000), 5)),
+			wantSQL: "SELECT department, count(*) FILTER (WHERE active = $1), (array_agg(name ORDER BY hired_at)) AS names " +
+				"FROM employees WHERE company_id = $2 GROUP BY department HAVING count(*) FILTER (WHERE salary > $3) > $4",
+			wantArgs: []any{true, 1, 1000, 5},
+		},
+		{
+			name:     "over",
+			b:        Select("id").Column(Over(Agg("sum", "amount").Filter("paid"), Window().OrderBy("id"))).From("orders").Where("id > ?", 3),
+			wantSQL:  "SELECT id, sum(amount) FILTER (WHERE paid) OVER (ORDER BY id) FROM orders WHERE id > $1",
+			wantArgs: []any{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestAggErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "no_name",
+			b:    Agg("", "*"),
+			want: "aggregate functions must have a name",
+		},
+		{
+			name: "distinct_no_args",
+			b:    Agg("count").Distinct(),
+			want: "DISTINCT aggregates must have an argument",
+		},
+		{
+			name: "distinct_order_by",
+			b:    Agg("array_agg", "name").Distinct().OrderBy("id"),
+			want: "in an aggregate with DISTINCT, ORDER BY expressions must appear in argument list",
+		},
+		{
+			name: "filter",
+			b:    Agg("count", "*").Filter(1),
+			want: "expected string-keyed map or string, not int",
+		},
+		{
+			name: "select",
+			b:    Select().Column(Agg("count", 1)).From("t"),
+			want: "expected string or SQLizer, not int",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleAgg() {
+	sql, args, _ := Select("department").
+		Column(Agg("array_agg", "name").Distinct().OrderBy("name DESC").Filter(Eq{"active": true})).
+		From("employees").
+		GroupBy("department").
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT department, array_agg(DISTINCT name ORDER BY name DESC) FILTER (WHERE active = $1) FROM employees GROUP BY department
+	// [true]
+}
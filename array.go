@@ -0,0 +1,199 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ArrayContains is syntactic sugar for use with the array @> operator, which
+// checks if the column has all the elements of the value.
+// Values can be a slice, array, or Valuer bound as an arg, or a SQLizer, such
+// as Array.
+// Ex:
+//
+//	.Where(ArrayContains{"tags": []string{"go", "sql"}}) == "tags @> ?"
+type ArrayContains map[string]any
+
+func (ac ArrayContains) toSQL(opr string) (sql string, args []any, err error) {
+	exprs := make([]string, 0, len(ac))
+	for _, key := range getSortedKeys(ac) {
+		var valSQL string
+		var valArgs []any
+		switch val := ac[key].(type) {
+		case nil:
+			err = fmt.Errorf("cannot use null with array operators")
+		case SQLizer:
+			valSQL, valArgs, err = nestedSQL(val)
+		default:
+			if _, ok := val.(Valuer); !ok && !isListType(val) {
+				err = fmt.Errorf("array operators need a slice or array, not %T", val)
+				break
+			}
+			valSQL, valArgs = "?", []any{val}
+		}
+		if err != nil {
+			return
+		}
+		exprs = append(exprs, fmt.Sprintf("%s %s %s", key, opr, valSQL))
+		args = append(args, valArgs...)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+func (ac ArrayContains) SQL() (string, []any, error) {
+	return resolvedSQL(ac)
+}
+
+func (ac ArrayContains) unfinalizedSQL() (sql string, args []any, err error) {
+	return ac.toSQL("@>")
+}
+
+// ArrayContainedBy is syntactic sugar for use with the array <@ operator,
+// which checks if all the elements of the column are in the value.
+// Ex:
+//
+//	.Where(ArrayContainedBy{"tags": []string{"go", "sql"}}) == "tags <@ ?"
+type ArrayContainedBy ArrayContains
+
+func (acb ArrayContainedBy) SQL() (string, []any, error) {
+	return resolvedSQL(acb)
+}
+
+func (acb ArrayContainedBy) unfinalizedSQL() (sql string, args []any, err error) {
+	return ArrayContains(acb).toSQL("<@")
+}
+
+// ArrayOverlap is syntactic sugar for use with the array && operator, which
+// checks if the column and the value have any elements in common.
+// Ex:
+//
+//	.Where(ArrayOverlap{"tags": []string{"go", "sql"}}) == "tags && ?"
+type ArrayOverlap ArrayContains
+
+func (ao ArrayOverlap) SQL() (string, []any, error) {
+	return resolvedSQL(ao)
+}
+
+func (ao ArrayOverlap) unfinalizedSQL() (sql string, args []any, err error) {
+	return ArrayContains(ao).toSQL("&&")
+}
+
+type arrayConstructor struct {
+	elems any
+}
+
+// Array is an ARRAY[...] constructor with the elements of the slice or array
+// elems, each bound as an arg, unless it is a SQLizer.
+// Ex:
+//
+//	Array([]int{1, 2, 3}) == "ARRAY[?, ?, ?]"
+//	.Where(ArrayOverlap{"tags": Array([]any{"go", Expr("lower(?)", "SQL")})})
+func Array(elems any) SQLizer {
+	return arrayConstructor{elems: elems}
+}
+
+func (a arrayConstructor) SQL() (string, []any, error) {
+	return resolvedSQL(a)
+}
+
+func (a arrayConstructor) unfinalizedSQL() (sql string, args []any, err error) {
+	if !isListType(a.elems) {
+		err = fmt.Errorf("ARRAY constructors need a slice or array, not %T", a.elems)
+		return
+	}
+	r := reflect.ValueOf(a.elems)
+	if r.Len() == 0 {
+		err = errors.New("ARRAY constructors must have at least one element")
+		return
+	}
+	parts := make([]SQLizer, r.Len())
+	for i := range parts {
+		parts[i] = newValuePart(r.Index(i).Interface())
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString("ARRAY[")
+	if args, err = appendSQL(parts, buf, ", ", nil); err != nil {
+		return
+	}
+	buf.WriteString("]")
+	sql = buf.String()
+	return
+}
+
+// arrayFunc is a function call with array arguments, where a string is a
+// column or expression, and a slice or array is bound as an arg.
+type arrayFunc struct {
+	name  string
+	array []any
+	args  []any
+}
+
+func (f arrayFunc) SQL() (string, []any, error) {
+	return resolvedSQL(f)
+}
+
+func (f arrayFunc) unfinalizedSQL() (sql string, args []any, err error) {
+	if len(f.array) == 0 {
+		err = fmt.Errorf("%s must have an array", f.name)
+		return
+	}
+	parts := make([]SQLizer, 0, len(f.array)+len(f.args))
+	for _, array := range f.array {
+		switch a := array.(type) {
+		case string, SQLizer:
+			parts = append(parts, newPart(a))
+		default:
+			if _, ok := a.(Valuer); !ok && !isListType(a) {
+				err = fmt.Errorf("%s needs a column, SQLizer, slice, or array, not %T", f.name, a)
+				return
+			}
+			parts = append(parts, newValuePart(a))
+		}
+	}
+	for _, arg := range f.args {
+		parts = append(parts, newValuePart(arg))
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString(f.name)
+	buf.WriteString("(")
+	if args, err = appendSQL(parts, buf, ", ", nil); err != nil {
+		return
+	}
+	buf.WriteString(")")
+	sql = buf.String()
+	return
+}
+
+// ArrayLength is the array_length(array, dimension) function, which returns
+// the length of the dimension of the array, or null if the array is empty.
+// array can be a column, a SQLizer, or a slice, array, or Valuer bound as an
+// arg.
+// Ex:
+//
+//	.Where(Expr("? > ?", ArrayLength("tags", 1), 2)) == "array_length(tags, ?) > ?"
+func ArrayLength(array any, dimension int) SQLizer {
+	return arrayFunc{name: "array_length", array: []any{array}, args: []any{dimension}}
+}
+
+// Cardinality is the cardinality(array) function, which returns the total
+// number of elements of the array, or 0 if it is empty.
+// Ex:
+//
+//	.Where(Expr("? = 0", Cardinality("tags"))) == "cardinality(tags) = 0"
+func Cardinality(array any) SQLizer {
+	return arrayFunc{name: "cardinality", array: []any{array}}
+}
+
+// Unnest is the unnest(array, ...) function, which expands the arrays to a set
+// of rows, to use in a FROM clause or join.
+// Ex:
+//
+//	JoinTable(Expr("? AS u(id, name)", Unnest([]int{1, 2}, []string{"a", "b"}))).Cross()
+//	// CROSS JOIN unnest(?, ?) AS u(id, name)
+func Unnest(arrays ...any) SQLizer {
+	return arrayFunc{name: "unnest", array: arrays}
+}
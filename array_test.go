@@ -0,0 +1,176 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type valuerArray string
+
+func (v valuerArray) Value() (any, error) {
+	return string(v), nil
+}
+
+func TestArray(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "contains",
+			b:        ArrayContains{"tags": []string{"go", "sql"}, "ids": [2]int{1, 2}},
+			wantSQL:  "ids @> ? AND tags @> ?",
+			wantArgs: []any{[2]int{1, 2}, []string{"go", "sql"}},
+		},
+		{
+			name:     "contains_valuer",
+			b:        ArrayContains{"tags": valuerArray("{go}")},
+			wantSQL:  "tags @> ?",
+			wantArgs: []any{valuerArray("{go}")},
+		},
+		{
+			name:     "contained_by",
+			b:        ArrayContainedBy{"tags": []string{"go"}},
+			wantSQL:  "tags <@ ?",
+			wantArgs: []any{[]string{"go"}},
+		},
+		{
+			name:     "overlap_array",
+			b:        ArrayOverlap{"tags": Array([]any{"go", Expr("lower(?)", "SQL")})},
+			wantSQL:  "tags && ARRAY[?, lower(?)]",
+			wantArgs: []any{"go", "SQL"},
+		},
+		{
+			name:     "array",
+			b:        Array([]int{1, 2, 3}),
+			wantSQL:  "ARRAY[?, ?, ?]",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "array_length",
+			b:        Expr("? > ?", ArrayLength("tags", 1), 2),
+			wantSQL:  "array_length(tags, ?) > ?",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:    "cardinality",
+			b:       Expr("? = 0", Cardinality(Ident("Tags"))),
+			wantSQL: `cardinality("Tags") = 0`,
+		},
+		{
+			name:     "cardinality_slice",
+			b:        Cardinality([]int{1, 2}),
+			wantSQL:  "cardinality(?)",
+			wantArgs: []any{[]int{1, 2}},
+		},
+		{
+			name:     "unnest",
+			b:        Unnest([]int{1, 2}, "names", Array([]string{"a"})),
+			wantSQL:  "unnest(?, names, ARRAY[?])",
+			wantArgs: []any{[]int{1, 2}, "a"},
+		},
+		{
+			name: "select",
+			b: Select("p.id", "u.tag").
+				From("posts p").
+				JoinClause(JoinTable(Expr("? AS u(tag)", Unnest("p.tags"))).Cross()).
+				Where("p.published = ?", true).
+				Where(ArrayOverlap{"p.tags": []string{"go", "sql"}}).
+				Where(ArrayContainedBy{"p.tags": Array([]string{"go", "sql", "pg"})}).
+				Where(Expr("? <= ?", Cardinality("p.tags"), 5)).
+				Where(Eq{"p.category": []int{1, 2}}),
+			wantSQL: "SELECT p.id, u.tag FROM posts p CROSS JOIN unnest(p.tags) AS u(tag) " +
+				"WHERE p.published = $1 AND p.tags && $2 AND p.tags <@ ARRAY[$3, $4, $5] AND cardinality(p.tags) <= $6 AND p.category = ANY ($7)",
+			wantArgs: []any{true, []string{"go", "sql"}, "go", "sql", "pg", 5, []int{1, 2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestArrayErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "contains_null",
+			b:    ArrayContains{"tags": nil},
+			want: "cannot use null with array operators",
+		},
+		{
+			name: "overlap_scalar",
+			b:    ArrayOverlap{"tags": "go"},
+			want: "array operators need a slice or array, not string",
+		},
+		{
+			name: "array_scalar",
+			b:    Array(1),
+			want: "ARRAY constructors need a slice or array, not int",
+		},
+		{
+			name: "array_empty",
+			b:    Array([]int{}),
+			want: "ARRAY constructors must have at least one element",
+		},
+		{
+			name: "cardinality_scalar",
+			b:    Cardinality(1),
+			want: "cardinality needs a column, SQLizer, slice, or array, not int",
+		},
+		{
+			name: "unnest_empty",
+			b:    Unnest(),
+			want: "unnest must have an array",
+		},
+		{
+			name: "contained_by_sqlizer",
+			b:    ArrayContainedBy{"tags": Array(nil)},
+			want: "ARRAY constructors need a slice or array, not <nil>",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleArrayOverlap() {
+	sql, args, _ := Select("id").
+		From("posts").
+		Where(ArrayOverlap{"tags": []string{"go", "sql"}}).
+		Where(NotLike{"title": []string{"%draft%", "%wip%"}}).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id FROM posts WHERE tags && $1 AND title NOT LIKE ALL ($2)
+	// [[go sql] [%draft% %wip%]]
+}
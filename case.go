@@ -54,7 +54,11 @@ type CaseBuilder struct {
 }
 
 // SQL builds the query into a SQL string and bound args.
-func (b CaseBuilder) SQL() (sqlStr string, args []any, err error) {
+func (b CaseBuilder) SQL() (string, []any, error) {
+	return resolvedSQL(b)
+}
+
+func (b CaseBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 	if len(b.whenParts) == 0 {
 		err = errors.New("case expression must contain at lease one WHEN clause")
 
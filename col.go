@@ -0,0 +1,279 @@
+package pgq
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ColExpr is a column or arithmetic expression, with methods to build
+// predicates on it, which can be combined with And and Or, and used in Where,
+// Having, and join conditions, in the order they are written.
+//
+// ColExpr is also a SQLizer itself, so it can be used as a value in
+// UpdateBuilder.Set, a result column, or compared to another column.
+//
+// Ex:
+//
+//	.Where(Col("age").GtOrEq(18)).Where(Col("orders.user_id").EqCol("users.id"))
+//	.Set("balance", Col("balance").Sub(amount))
+type ColExpr struct {
+	expr  SQLizer
+	arith bool
+}
+
+// Col returns a ColExpr for the column name, such as "users.age".
+// name isn't quoted, see Ident for quoted identifiers.
+func Col(name string) ColExpr {
+	return ColExpr{expr: newPart(name)}
+}
+
+// SQL returns the column or arithmetic expression.
+func (c ColExpr) SQL() (string, []any, error) {
+	return resolvedSQL(c)
+}
+
+func (c ColExpr) unfinalizedSQL() (sql string, args []any, err error) {
+	if c.expr == nil {
+		err = errors.New("columns must have a name")
+		return
+	}
+	sql, args, err = nestedSQL(c.expr)
+	if err == nil && sql == "" {
+		err = errors.New("columns must have a name")
+	}
+	return
+}
+
+// operandSQL returns the SQL of v as an operand of an operator: a column,
+// function call, or cast as is, another SQLizer in parentheses, and a value as
+// a placeholder.
+func operandSQL(v any) (string, []any, error) {
+	switch s := v.(type) {
+	case ColExpr:
+		sql, args, err := nestedSQL(s)
+		if s.arith {
+			sql = "(" + sql + ")"
+		}
+		return sql, args, err
+	case funcCall, cast:
+		return nestedSQL(s.(SQLizer))
+	case SQLizer:
+		sql, args, err := nestedSQL(s)
+		return "(" + sql + ")", args, err
+	}
+	return "?", []any{v}, nil
+}
+
+type colPredicate struct {
+	col    ColExpr
+	format string
+	values []any
+	err    error
+}
+
+func (p colPredicate) SQL() (string, []any, error) {
+	return resolvedSQL(p)
+}
+
+func (p colPredicate) unfinalizedSQL() (sql string, args []any, err error) {
+	if p.err != nil {
+		return "", nil, p.err
+	}
+	operands := make([]any, 0, 1+len(p.values))
+	for _, v := range append([]any{p.col}, p.values...) {
+		var vSQL string
+		var vArgs []any
+		if vSQL, vArgs, err = operandSQL(v); err != nil {
+			return
+		}
+		operands = append(operands, vSQL)
+		args = append(args, vArgs...)
+	}
+	sql = fmt.Sprintf(p.format, operands...)
+	return
+}
+
+func (c ColExpr) predicate(format string, values ...any) SQLizer {
+	return colPredicate{col: c, format: format, values: values}
+}
+
+// isNilValue reports whether v is nil or a nil pointer.
+func isNilValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	r := reflect.ValueOf(v)
+	return r.Kind() == reflect.Ptr && r.IsNil()
+}
+
+// Eq is the c = v predicate, or c IS NULL if v is nil.
+// v is bound as an arg, unless it is a SQLizer, such as another Col.
+func (c ColExpr) Eq(v any) SQLizer {
+	if isNilValue(v) {
+		return c.IsNull()
+	}
+	return c.predicate("%s = %s", v)
+}
+
+// NotEq is the c <> v predicate, or c IS NOT NULL if v is nil.
+func (c ColExpr) NotEq(v any) SQLizer {
+	if isNilValue(v) {
+		return c.IsNotNull()
+	}
+	return c.predicate("%s <> %s", v)
+}
+
+// EqCol is the c = other predicate, comparing two columns.
+func (c ColExpr) EqCol(other string) SQLizer {
+	return c.predicate("%s = %s", Col(other))
+}
+
+// Lt is the c < v predicate.
+func (c ColExpr) Lt(v any) SQLizer {
+	return c.predicate("%s < %s", v)
+}
+
+// LtOrEq is the c <= v predicate.
+func (c ColExpr) LtOrEq(v any) SQLizer {
+	return c.predicate("%s <= %s", v)
+}
+
+// Gt is the c > v predicate.
+func (c ColExpr) Gt(v any) SQLizer {
+	return c.predicate("%s > %s", v)
+}
+
+// GtOrEq is the c >= v predicate.
+func (c ColExpr) GtOrEq(v any) SQLizer {
+	return c.predicate("%s >= %s", v)
+}
+
+// IsNull is the c IS NULL predicate.
+func (c ColExpr) IsNull() SQLizer {
+	return c.predicate("%s IS NULL")
+}
+
+// IsNotNull is the c IS NOT NULL predicate.
+func (c ColExpr) IsNotNull() SQLizer {
+	return c.predicate("%s IS NOT NULL")
+}
+
+// In is the c = ANY (values) predicate for a slice or array bound as an arg,
+// or c IN (values) for a SelectBuilder subquery.
+// An empty slice or array is always false, like in Eq, and nil, such as a nil
+// *SelectBuilder, is an error.
+func (c ColExpr) In(values any) SQLizer {
+	return c.in(values, "=", "ANY", "IN", sqlFalse)
+}
+
+// NotIn is the c <> ALL (values) predicate for a slice or array bound as an
+// arg, or c NOT IN (values) for a SelectBuilder subquery.
+// An empty slice or array is always true, like in NotEq.
+func (c ColExpr) NotIn(values any) SQLizer {
+	return c.in(values, "<>", "ALL", "NOT IN", sqlTrue)
+}
+
+func (c ColExpr) in(values any, opr, quantifier, inOpr, emptyExpr string) SQLizer {
+	if isNilValue(values) {
+		return colPredicate{err: fmt.Errorf("%s needs a slice, array, or subquery, not nil", inOpr)}
+	}
+	if p, ok := values.(*SelectBuilder); ok {
+		values = *p
+	}
+	switch v := values.(type) {
+	case SelectBuilder:
+		return c.predicate("%s "+inOpr+" %s", v)
+	case SQLizer:
+		return c.predicate("%s "+opr+" "+quantifier+" (%s)", ColExpr{expr: v})
+	}
+	if !isListType(values) {
+		return colPredicate{err: fmt.Errorf("%s needs a slice, array, or subquery, not %T", inOpr, values)}
+	}
+	if reflect.ValueOf(values).Len() == 0 {
+		return Expr(emptyExpr)
+	}
+	return c.predicate("%s "+opr+" "+quantifier+" (%s)", values)
+}
+
+// Like is the c LIKE pattern predicate.
+func (c ColExpr) Like(pattern any) SQLizer {
+	return c.predicate("%s LIKE %s", pattern)
+}
+
+// NotLike is the c NOT LIKE pattern predicate.
+func (c ColExpr) NotLike(pattern any) SQLizer {
+	return c.predicate("%s NOT LIKE %s", pattern)
+}
+
+// ILike is the c ILIKE pattern predicate, which is case-insensitive.
+func (c ColExpr) ILike(pattern any) SQLizer {
+	return c.predicate("%s ILIKE %s", pattern)
+}
+
+// NotILike is the c NOT ILIKE pattern predicate.
+func (c ColExpr) NotILike(pattern any) SQLizer {
+	return c.predicate("%s NOT ILIKE %s", pattern)
+}
+
+// Between is the c BETWEEN low AND high predicate.
+func (c ColExpr) Between(low, high any) SQLizer {
+	return c.predicate("%s BETWEEN %s AND %s", low, high)
+}
+
+// NotBetween is the c NOT BETWEEN low AND high predicate.
+func (c ColExpr) NotBetween(low, high any) SQLizer {
+	return c.predicate("%s NOT BETWEEN %s AND %s", low, high)
+}
+
+// arithExpr is a binary arithmetic expression.
+type arithExpr colPredicate
+
+func (a arithExpr) SQL() (string, []any, error) {
+	return resolvedSQL(a)
+}
+
+func (a arithExpr) unfinalizedSQL() (sql string, args []any, err error) {
+	return colPredicate(a).unfinalizedSQL()
+}
+
+func (c ColExpr) arithmetic(opr string, v any) ColExpr {
+	return ColExpr{
+		expr:  arithExpr{col: c, format: "%s " + opr + " %s", values: []any{v}},
+		arith: true,
+	}
+}
+
+// Add returns the c + v expression.
+//
+// Ex:
+//
+//	Col("price").Add(10).Mul(2) == "(price + ?) * ?"
+func (c ColExpr) Add(v any) ColExpr {
+	return c.arithmetic("+", v)
+}
+
+// Sub returns the c - v expression.
+func (c ColExpr) Sub(v any) ColExpr {
+	return c.arithmetic("-", v)
+}
+
+// Mul returns the c * v expression.
+func (c ColExpr) Mul(v any) ColExpr {
+	return c.arithmetic("*", v)
+}
+
+// Div returns the c / v expression.
+func (c ColExpr) Div(v any) ColExpr {
+	return c.arithmetic("/", v)
+}
+
+// Asc returns the ascending order of c, to use with Sort.
+func (c ColExpr) Asc() Order {
+	return Asc(c)
+}
+
+// Desc returns the descending order of c, to use with Sort.
+func (c ColExpr) Desc() Order {
+	return Desc(c)
+}
@@ -0,0 +1,232 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCol(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "eq",
+			b:        Col("users.age").Eq(18),
+			wantSQL:  "users.age = ?",
+			wantArgs: []any{18},
+		},
+		{
+			name:    "eq_nil",
+			b:       Col("deleted_at").Eq(nil),
+			wantSQL: "deleted_at IS NULL",
+		},
+		{
+			name:    "not_eq_nil_pointer",
+			b:       Col("deleted_at").NotEq((*int)(nil)),
+			wantSQL: "deleted_at IS NOT NULL",
+		},
+		{
+			name:     "comparisons",
+			b:        And{Col("a").NotEq(1), Col("b").Lt(2), Col("c").LtOrEq(3), Col("d").Gt(4), Col("e").GtOrEq(5)},
+			wantSQL:  "(a <> ? AND b < ? AND c <= ? AND d > ? AND e >= ?)",
+			wantArgs: []any{1, 2, 3, 4, 5},
+		},
+		{
+			name:    "eq_col",
+			b:       Col("orders.user_id").EqCol("users.id"),
+			wantSQL: "orders.user_id = users.id",
+		},
+		{
+			name:     "gt_col_arithmetic",
+			b:        Col("total").Gt(Col("subtotal").Add(Col("tax")).Mul(2)),
+			wantSQL:  "total > ((subtotal + tax) * ?)",
+			wantArgs: []any{2},
+		},
+		{
+			name:     "lt_subquery",
+			b:        Col("price").Lt(Select("avg(price)").From("products").Where("category = ?", "books")),
+			wantSQL:  "price < (SELECT avg(price) FROM products WHERE category = ?)",
+			wantArgs: []any{"books"},
+		},
+		{
+			name:     "in",
+			b:        Col("id").In([]int{1, 2, 3}),
+			wantSQL:  "id = ANY (?)",
+			wantArgs: []any{[]int{1, 2, 3}},
+		},
+		{
+			name:     "in_typed",
+			b:        Col("id").In(Typed([]string{"a"}, "uuid[]")),
+			wantSQL:  "id = ANY (?::uuid[])",
+			wantArgs: []any{[]string{"a"}},
+		},
+		{
+			name:     "not_in_subquery",
+			b:        Col("id").NotIn(ptr(Select("user_id").From("bans").Where("active = ?", true))),
+			wantSQL:  "id NOT IN (SELECT user_id FROM bans WHERE active = ?)",
+			wantArgs: []any{true},
+		},
+		{
+			name:    "in_empty",
+			b:       Or{Col("id").In([]int{}), Col("id").NotIn([]int{})},
+			wantSQL: "((FALSE) OR (TRUE))",
+		},
+		{
+			name:    "is_not_null",
+			b:       Col("email").IsNotNull(),
+			wantSQL: "email IS NOT NULL",
+		},
+		{
+			name:     "like",
+			b:        Or{Col("name").Like("a%"), Col("name").NotLike("b%"), Col("name").ILike("c%"), Col("name").NotILike("d%")},
+			wantSQL:  "(name LIKE ? OR name NOT LIKE ? OR name ILIKE ? OR name NOT ILIKE ?)",
+			wantArgs: []any{"a%", "b%", "c%", "d%"},
+		},
+		{
+			name:     "between",
+			b:        And{Col("age").Between(18, 65), Col("score").NotBetween(Col("min_score"), Expr("?", 100))},
+			wantSQL:  "(age BETWEEN ? AND ? AND score NOT BETWEEN min_score AND (?))",
+			wantArgs: []any{18, 65, 100},
+		},
+		{
+			name:     "func_cast",
+			b:        Col("created_at").Sub(Cast(Col("age"), "interval")).Gt(Func("to_timestamp", 0)),
+			wantSQL:  "(created_at - CAST(age AS interval)) > to_timestamp(?)",
+			wantArgs: []any{0},
+		},
+		{
+			name:     "arithmetic",
+			b:        Col("price").Sub(5).Div(Col("qty").Add(1)),
+			wantSQL:  "(price - ?) / (qty + ?)",
+			wantArgs: []any{5, 1},
+		},
+		{
+			name: "select",
+			b: Select("u.name").
+				Column(Col("u.age").Add(1)).
+				From("users u").
+				Join("orders o ON o.user_id = u.id").
+				Where(Col("u.age").GtOrEq(18)).
+				Where(Or{Col("u.country").Eq("NL"), Col("u.country").IsNull()}).
+				GroupBy("u.name", "u.age").
+				Having(Col("count(o.id)").Gt(2)).
+				Sort(Col("u.age").Desc(), Col("u.name").Asc().NullsLast()),
+			wantSQL: "SELECT u.name, u.age + $1 FROM users u JOIN orders o ON o.user_id = u.id " +
+				"WHERE u.age >= $2 AND (u.country = $3 OR u.country IS NULL) GROUP BY u.name, u.age HAVING count(o.id) > $4 " +
+				"ORDER BY u.age DESC, u.name ASC NULLS LAST",
+			wantArgs: []any{1, 18, "NL", 2},
+		},
+		{
+			name: "update",
+			b: Update("accounts").
+				Set("balance", Col("balance").Sub(10)).
+				Set("updated_at", Now()).
+				Where(Col("id").Eq(1)).
+				Where(Col("balance").GtOrEq(10)),
+			wantSQL:  "UPDATE accounts SET balance = balance - $1, updated_at = now() WHERE id = $2 AND balance >= $3",
+			wantArgs: []any{10, 1, 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestColErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "zero",
+			b:    ColExpr{}.Eq(1),
+			want: "columns must have a name",
+		},
+		{
+			name: "empty",
+			b:    Col("").IsNull(),
+			want: "columns must have a name",
+		},
+		{
+			name: "eq_col_empty",
+			b:    Col("a").EqCol(""),
+			want: "columns must have a name",
+		},
+		{
+			name: "in",
+			b:    Col("id").In(1),
+			want: "IN needs a slice, array, or subquery, not int",
+		},
+		{
+			name: "not_in",
+			b:    Col("id").NotIn("a"),
+			want: "NOT IN needs a slice, array, or subquery, not string",
+		},
+		{
+			name: "in_nil_subquery",
+			b:    Col("id").In((*SelectBuilder)(nil)),
+			want: "IN needs a slice, array, or subquery, not nil",
+		},
+		{
+			name: "not_in_nil",
+			b:    Col("id").NotIn(nil),
+			want: "NOT IN needs a slice, array, or subquery, not nil",
+		},
+		{
+			name: "subquery",
+			b:    Col("id").In(Select()),
+			want: "select statements must have at least one result column",
+		},
+		{
+			name: "arithmetic",
+			b:    Col("a").Add(Col("")).Gt(1),
+			want: "columns must have a name",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleCol() {
+	sql, args, _ := Select("u.name").
+		From("users u").
+		Join("orders o ON o.user_id = u.id").
+		Where(Col("o.shipped_at").Gt(Col("o.created_at").Add(Typed("2 days", "interval")))).
+		Where(Or{Col("u.age").Between(18, 65), Col("u.vip").Eq(true)}).
+		Sort(Col("u.name").Asc()).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT u.name FROM users u JOIN orders o ON o.user_id = u.id WHERE o.shipped_at > (o.created_at + $1::interval) AND (u.age BETWEEN $2 AND $3 OR u.vip = $4) ORDER BY u.name ASC
+	// [2 days 18 65 true]
+}
@@ -0,0 +1,107 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CTE is a common table expression (WITH query) attached to a statement.
+//
+// Expr can be any SQLizer, including data-modifying statements such as
+// DeleteBuilder with a RETURNING clause.
+//
+// Ex:
+//
+//	.WithCTE(CTE{Name: "t", Columns: []string{"n"}, Recursive: true, Expr: q})
+type CTE struct {
+	Name    string
+	Columns []string
+	Expr    SQLizer
+
+	// Recursive turns the WITH clause into WITH RECURSIVE.
+	Recursive bool
+
+	// Materialized and NotMaterialized control the MATERIALIZED option.
+	Materialized    bool
+	NotMaterialized bool
+}
+
+// SQL returns the CTE definition, without the WITH keyword.
+func (c CTE) SQL() (string, []any, error) {
+	return resolvedSQL(c)
+}
+
+func (c CTE) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if c.Name == "" {
+		err = errors.New("common table expressions must have a name")
+		return
+	}
+	if c.Expr == nil {
+		err = fmt.Errorf("common table expression %q must have a query", c.Name)
+		return
+	}
+	if c.Materialized && c.NotMaterialized {
+		err = fmt.Errorf("common table expression %q cannot be both MATERIALIZED and NOT MATERIALIZED", c.Name)
+		return
+	}
+
+	exprSQL, args, err := nestedSQL(c.Expr)
+	if err != nil {
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString(c.Name)
+	if len(c.Columns) > 0 {
+		sql.WriteString("(")
+		sql.WriteString(strings.Join(c.Columns, ", "))
+		sql.WriteString(")")
+	}
+	sql.WriteString(" AS ")
+	if c.Materialized {
+		sql.WriteString("MATERIALIZED ")
+	}
+	if c.NotMaterialized {
+		sql.WriteString("NOT MATERIALIZED ")
+	}
+	sql.WriteString("(")
+	sql.WriteString(exprSQL)
+	sql.WriteString(")")
+
+	sqlStr = sql.String()
+	return
+}
+
+// appendCTEs writes the WITH clause for ctes, followed by a space.
+func appendCTEs(ctes []CTE, w io.Writer, args []any) ([]any, error) {
+	if len(ctes) == 0 {
+		return args, nil
+	}
+
+	keyword := "WITH "
+	parts := make([]SQLizer, 0, len(ctes))
+	names := make(map[string]bool, len(ctes))
+	for _, cte := range ctes {
+		if names[cte.Name] {
+			return nil, fmt.Errorf("common table expression name %q specified more than once", cte.Name)
+		}
+		names[cte.Name] = true
+		if cte.Recursive {
+			keyword = "WITH RECURSIVE "
+		}
+		parts = append(parts, cte)
+	}
+
+	if _, err := io.WriteString(w, keyword); err != nil {
+		return nil, err
+	}
+	args, err := appendSQL(parts, w, ", ", args)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(w, " ")
+	return args, err
+}
@@ -0,0 +1,190 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCTE(t *testing.T) {
+	t.Parallel()
+	recent := Select("id").From("orders").Where("created_at > ?", "2024-01-01")
+	moved := Delete("products").Where("sold = ?", true).Returning("*")
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "select",
+			b:        Select("*").With("recent", recent).From("recent").Where("id > ?", 10),
+			wantSQL:  "WITH recent AS (SELECT id FROM orders WHERE created_at > $1) SELECT * FROM recent WHERE id > $2",
+			wantArgs: []any{"2024-01-01", 10},
+		},
+		{
+			name: "select_multiple",
+			b: Select("*").
+				With("a", Select("x").From("t1").Where("x = ?", 1)).
+				With("b", Select("y").From("t2").Where("y = ?", 2)).
+				From("a, b"),
+			wantSQL: "WITH a AS (SELECT x FROM t1 WHERE x = $1), b AS (SELECT y FROM t2 WHERE y = $2) " +
+				"SELECT * FROM a, b",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "columns_materialized",
+			b: Select("*").
+				WithCTE(CTE{Name: "a", Columns: []string{"x", "y"}, Materialized: true, Expr: Expr("VALUES (?, ?)", 1, 2)}).
+				WithCTE(CTE{Name: "b", NotMaterialized: true, Expr: Select("1")}).
+				From("a, b"),
+			wantSQL:  "WITH a(x, y) AS MATERIALIZED (VALUES ($1, $2)), b AS NOT MATERIALIZED (SELECT 1) SELECT * FROM a, b",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "recursive",
+			b: Select("n").
+				WithRecursive("t", Expr("VALUES (?) UNION ALL ?", 1, Select("n+1").From("t").Where("n < ?", 100))).
+				From("t"),
+			wantSQL:  "WITH RECURSIVE t AS (VALUES ($1) UNION ALL SELECT n+1 FROM t WHERE n < $2) SELECT n FROM t",
+			wantArgs: []any{1, 100},
+		},
+		{
+			name: "recursive_not_first",
+			b: Select("*").
+				With("a", Select("1")).
+				WithCTE(CTE{Name: "t", Columns: []string{"n"}, Recursive: true, Expr: Expr("SELECT 1 UNION ALL SELECT n+1 FROM t")}).
+				From("t"),
+			wantSQL: "WITH RECURSIVE a AS (SELECT 1), t(n) AS (SELECT 1 UNION ALL SELECT n+1 FROM t) SELECT * FROM t",
+		},
+		{
+			name: "insert_data_modifying",
+			b: Insert("archive").
+				Prefix("WITH prefix AS ?", 0).
+				With("moved", moved).
+				Select(Select("*").From("moved").Where("price > ?", 5)),
+			wantSQL: "WITH prefix AS $1 WITH moved AS (DELETE FROM products WHERE sold = $2 RETURNING *) " +
+				"INSERT INTO archive SELECT * FROM moved WHERE price > $3",
+			wantArgs: []any{0, true, 5},
+		},
+		{
+			name: "update",
+			b: Update("t").
+				With("u", Update("other").Set("x", 1).Returning("id")).
+				Set("y", 2).
+				Where("id IN (SELECT id FROM u)"),
+			wantSQL:  "WITH u AS (UPDATE other SET x = $1 RETURNING id) UPDATE t SET y = $2 WHERE id IN (SELECT id FROM u)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "delete",
+			b:        Delete("t").With("old", recent).Where("id IN (SELECT id FROM old) AND x = ?", 3),
+			wantSQL:  "WITH old AS (SELECT id FROM orders WHERE created_at > $1) DELETE FROM t WHERE id IN (SELECT id FROM old) AND x = $2",
+			wantArgs: []any{"2024-01-01", 3},
+		},
+		{
+			name:     "statement_select",
+			b:        With("recent", recent).Select("*").From("recent").Where("id > ?", 10),
+			wantSQL:  "WITH recent AS (SELECT id FROM orders WHERE created_at > $1) SELECT * FROM recent WHERE id > $2",
+			wantArgs: []any{"2024-01-01", 10},
+		},
+		{
+			name: "statement_insert",
+			b:    With("moved", moved).Insert("archive").Select(Select("*").From("moved")),
+			wantSQL: "WITH moved AS (DELETE FROM products WHERE sold = $1 RETURNING *) " +
+				"INSERT INTO archive SELECT * FROM moved",
+			wantArgs: []any{true},
+		},
+		{
+			name:     "statement_update",
+			b:        WithRecursive("r", Expr("SELECT ?", 1)).Update("t").Set("x", 2),
+			wantSQL:  "WITH RECURSIVE r AS (SELECT $1) UPDATE t SET x = $2",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "statement_delete",
+			b:        Statement().With("r", Expr("SELECT ?", 1)).Where("a = ?", 2).Delete("t"),
+			wantSQL:  "WITH r AS (SELECT $1) DELETE FROM t WHERE a = $2",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "nested_select",
+			b:        Select("*").FromSelect(Select("*").With("r", Expr("SELECT ?", 1)).From("r"), "s").Where("x = ?", 2),
+			wantSQL:  "SELECT * FROM (WITH r AS (SELECT $1) SELECT * FROM r) AS s WHERE x = $2",
+			wantArgs: []any{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestCTEErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "no_name",
+			b:    Select("*").With("", Select("1")),
+			want: "common table expressions must have a name",
+		},
+		{
+			name: "no_query",
+			b:    Select("*").With("a", nil),
+			want: `common table expression "a" must have a query`,
+		},
+		{
+			name: "materialized",
+			b:    Select("*").WithCTE(CTE{Name: "a", Expr: Select("1"), Materialized: true, NotMaterialized: true}),
+			want: `common table expression "a" cannot be both MATERIALIZED and NOT MATERIALIZED`,
+		},
+		{
+			name: "duplicate",
+			b:    Delete("t").With("a", Select("1")).With("a", Select("2")),
+			want: `common table expression name "a" specified more than once`,
+		},
+		{
+			name: "query_error",
+			b:    Update("t").Set("x", 1).With("a", Select()),
+			want: "select statements must have at least one result column",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleWith() {
+	moved := Delete("products").Where("sold = ?", true).Returning("*")
+	sql, args, _ := With("moved", moved).Insert("archive").Select(Select("*").From("moved")).SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// WITH moved AS (DELETE FROM products WHERE sold = $1 RETURNING *) INSERT INTO archive SELECT * FROM moved
+	// [true]
+}
@@ -3,18 +3,22 @@ package pgq
 import (
 	"bytes"
 	"fmt"
-	"strings"
 )
 
 // DeleteBuilder builds SQL DELETE statements.
 type DeleteBuilder struct {
 	prefixes   []SQLizer
+	ctes       []CTE
 	from       string
 	usingParts []SQLizer
 	whereParts []SQLizer
-	orderBys   []string
+	orderBys   []SQLizer
 	returning  []SQLizer
 	suffixes   []SQLizer
+
+	// err is the first error of a method, such as an invalid identifier,
+	// returned by SQL.
+	err error
 }
 
 // SQL builds the query into a SQL string and bound args.
@@ -29,6 +33,10 @@ func (b DeleteBuilder) SQL() (sqlStr string, args []any, err error) {
 }
 
 func (b DeleteBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if b.err != nil {
+		err = b.err
+		return
+	}
 	if b.from == "" {
 		err = fmt.Errorf("delete statements must specify a From table")
 		return
@@ -45,6 +53,11 @@ func (b DeleteBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" ")
 	}
 
+	args, err = appendCTEs(b.ctes, sql, args)
+	if err != nil {
+		return
+	}
+
 	sql.WriteString("DELETE FROM ")
 	sql.WriteString(b.from)
 
@@ -66,7 +79,10 @@ func (b DeleteBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 
 	if len(b.orderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		args, err = appendSQL(b.orderBys, sql, ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.returning) > 0 {
@@ -110,12 +126,45 @@ func (b DeleteBuilder) PrefixExpr(expr SQLizer) DeleteBuilder {
 	return b
 }
 
+// With adds a common table expression to the WITH clause of the query.
+func (b DeleteBuilder) With(name string, expr SQLizer) DeleteBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr})
+}
+
+// WithRecursive adds a common table expression to the WITH clause of the query,
+// turning it into WITH RECURSIVE.
+func (b DeleteBuilder) WithRecursive(name string, expr SQLizer) DeleteBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr, Recursive: true})
+}
+
+// WithCTE adds a common table expression to the WITH clause of the query.
+func (b DeleteBuilder) WithCTE(cte CTE) DeleteBuilder {
+	b.ctes = append(b.ctes, cte)
+	return b
+}
+
 // From sets the table to be deleted from.
 func (b DeleteBuilder) From(from string) DeleteBuilder {
 	b.from = from
 	return b
 }
 
+// FromIdent sets the table to be deleted from, quoted as an identifier.
+//
+// See QuoteIdent.
+func (b DeleteBuilder) FromIdent(from string) DeleteBuilder {
+	b = b.setErr(validateIdent(from))
+	return b.From(QuoteIdent(from))
+}
+
+// setErr keeps err to be returned by SQL, unless there is an earlier error.
+func (b DeleteBuilder) setErr(err error) DeleteBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
 // Using adds USING expressions to the query.
 //
 // A table expression allowing columns from other tables to appear in the WHERE condition.
@@ -144,9 +193,17 @@ func (b DeleteBuilder) Where(pred any, args ...any) DeleteBuilder {
 	return b
 }
 
+// OrderByClause adds ORDER BY clause to the query.
+func (b DeleteBuilder) OrderByClause(pred any, args ...any) DeleteBuilder {
+	b.orderBys = append(b.orderBys, newPart(pred, args...))
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b DeleteBuilder) OrderBy(orderBys ...string) DeleteBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	for _, orderBy := range orderBys {
+		b = b.OrderByClause(orderBy)
+	}
 	return b
 }
 
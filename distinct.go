@@ -0,0 +1,131 @@
+package pgq
+
+import (
+	"errors"
+	"strings"
+)
+
+// orderItem is an ORDER BY or DISTINCT ON expression, without its sort options.
+type orderItem struct {
+	sql  string
+	args []any
+}
+
+// equal reports whether i and other are the same expression for PostgreSQL.
+// Expressions with args never are, as each arg is bound to its own placeholder.
+func (i orderItem) equal(other orderItem) bool {
+	return i.sql == other.sql && len(i.args) == 0 && len(other.args) == 0
+}
+
+// validateDistinctOn checks that the leftmost ORDER BY expressions match the
+// DISTINCT ON expressions, as PostgreSQL requires.
+func (b SelectBuilder) validateDistinctOn() error {
+	if len(b.distinctOn) == 0 {
+		return nil
+	}
+	for _, option := range b.options {
+		if strings.EqualFold(strings.TrimSpace(option), "DISTINCT") {
+			return errors.New("select statements cannot have both DISTINCT and DISTINCT ON")
+		}
+	}
+
+	distinctOn, err := orderItems(b.distinctOn)
+	if err != nil {
+		return err
+	}
+	orderBy, err := orderItems(b.orderByParts)
+	if err != nil {
+		return err
+	}
+
+	matched := make([]bool, len(distinctOn))
+	remaining := len(distinctOn)
+	for _, o := range orderBy {
+		if remaining == 0 {
+			break
+		}
+		found := false
+		for i, d := range distinctOn {
+			if o.equal(d) {
+				found = true
+				if !matched[i] {
+					matched[i] = true
+					remaining--
+				}
+			}
+		}
+		if !found {
+			return errors.New("SELECT DISTINCT ON expressions must match initial ORDER BY expressions")
+		}
+	}
+	return nil
+}
+
+// orderItems renders parts, splitting comma-separated lists of expressions
+// with their args, and removing the sort options, such as ASC or NULLS LAST.
+func orderItems(parts []SQLizer) ([]orderItem, error) {
+	var items []orderItem
+	for _, p := range parts {
+		sql, args, err := nestedSQL(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, expr := range splitTopLevel(sql) {
+			n := countPlaceholders(expr)
+			if n > len(args) {
+				n = len(args)
+			}
+			items = append(items, orderItem{sql: trimSortOptions(expr), args: args[:n]})
+			args = args[n:]
+		}
+	}
+	return items, nil
+}
+
+// splitTopLevel splits sql on the commas that are outside of parentheses,
+// brackets, and quotes.
+func splitTopLevel(sql string) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, sql[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, sql[start:])
+}
+
+// trimSortOptions removes ASC, DESC, USING operator, and NULLS FIRST or NULLS LAST
+// from the end of an ORDER BY expression, and normalizes its whitespace.
+func trimSortOptions(expr string) string {
+	fields := strings.Fields(expr)
+	n := len(fields)
+	if n > 2 && strings.EqualFold(fields[n-2], "NULLS") &&
+		(strings.EqualFold(fields[n-1], "FIRST") || strings.EqualFold(fields[n-1], "LAST")) {
+		n -= 2
+	}
+	switch {
+	case n > 1 && (strings.EqualFold(fields[n-1], "ASC") || strings.EqualFold(fields[n-1], "DESC")):
+		n--
+	case n > 2 && strings.EqualFold(fields[n-2], "USING"):
+		n -= 2
+	}
+	return strings.Join(fields[:n], " ")
+}
@@ -0,0 +1,131 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuilderDistinctOn(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SelectBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "no_order_by",
+			b:       Select("a", "b").DistinctOn("a").From("t"),
+			wantSQL: "SELECT DISTINCT ON (a) a, b FROM t",
+		},
+		{
+			name:    "order_by",
+			b:       Select("location", "time", "report").DistinctOn("location").From("weather_reports").OrderBy("location", "time DESC"),
+			wantSQL: "SELECT DISTINCT ON (location) location, time, report FROM weather_reports ORDER BY location, time DESC",
+		},
+		{
+			name:    "different_order",
+			b:       Select("*").DistinctOn("a", "b").From("t").OrderBy("b DESC NULLS LAST, a", "c"),
+			wantSQL: "SELECT DISTINCT ON (a, b) * FROM t ORDER BY b DESC NULLS LAST, a, c",
+		},
+		{
+			name:    "shorter_order_by",
+			b:       Select("*").DistinctOn("a", "b").From("t").OrderBy("a USING >"),
+			wantSQL: "SELECT DISTINCT ON (a, b) * FROM t ORDER BY a USING >",
+		},
+		{
+			name: "args",
+			b: Select("*").
+				DistinctOn("kind", Expr("date_trunc(?, ts)", "day")).
+				From("events").
+				Where("ts > ?", "2024-01-01").
+				OrderBy("kind"),
+			wantSQL:  "SELECT DISTINCT ON (kind, date_trunc($1, ts)) * FROM events WHERE ts > $2 ORDER BY kind",
+			wantArgs: []any{"day", "2024-01-01"},
+		},
+		{
+			name:    "repeated",
+			b:       Select("*").DistinctOn("a").From("t").OrderBy("a", "a DESC", "b"),
+			wantSQL: "SELECT DISTINCT ON (a) * FROM t ORDER BY a, a DESC, b",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestSelectBuilderDistinctOnErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SelectBuilder
+		want string
+	}{
+		{
+			name: "order_by_mismatch",
+			b:    Select("*").DistinctOn("a").From("t").OrderBy("b", "a"),
+			want: "SELECT DISTINCT ON expressions must match initial ORDER BY expressions",
+		},
+		{
+			name: "order_by_partial_mismatch",
+			b:    Select("*").DistinctOn("a", "b").From("t").OrderBy("a, c, b"),
+			want: "SELECT DISTINCT ON expressions must match initial ORDER BY expressions",
+		},
+		{
+			name: "args",
+			b:    Select("*").DistinctOn(Expr("date_trunc(?, ts)", "day")).From("t").OrderByClause("date_trunc(?, ts)", "day"),
+			want: "SELECT DISTINCT ON expressions must match initial ORDER BY expressions",
+		},
+		{
+			name: "distinct",
+			b:    Select("*").Distinct().DistinctOn("a").From("t"),
+			want: "select statements cannot have both DISTINCT and DISTINCT ON",
+		},
+		{
+			name: "set_operation",
+			b:    Union(Select("a").From("t"), Select("a").From("u")).DistinctOn("a"),
+			want: "set operations cannot have result columns, FROM, WHERE, GROUP BY, HAVING, or WINDOW clauses; use a subquery instead",
+		},
+		{
+			name: "for_update",
+			b:    Select("*").DistinctOn("a").From("t").ForUpdate(),
+			want: "FOR UPDATE cannot be used with DISTINCT",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleSelectBuilder_DistinctOn() {
+	sql, _, _ := Select("location", "time", "report").
+		DistinctOn("location").
+		From("weather_reports").
+		OrderBy("location", "time DESC").
+		SQL()
+	fmt.Println(sql)
+	// Output:
+	// SELECT DISTINCT ON (location) location, time, report FROM weather_reports ORDER BY location, time DESC
+}
@@ -22,14 +22,28 @@ type expr struct {
 
 // Expr builds an expression from a SQL fragment and arguments.
 //
+// If the only argument is Named, the fragment uses @name references instead
+// of "?" placeholders.
+//
 // Ex:
 //
 //	Expr("FROM_UNIXTIME(?)", t)
+//	Expr("created_at > @since", Named{"since": t})
 func Expr(sql string, args ...any) SQLizer {
 	return expr{sql: sql, args: args}
 }
 
-func (e expr) SQL() (sql string, args []any, err error) {
+func (e expr) SQL() (string, []any, error) {
+	return resolvedSQL(e)
+}
+
+func (e expr) unfinalizedSQL() (sql string, args []any, err error) {
+	if len(e.args) == 1 {
+		if named, ok := e.args[0].(Named); ok {
+			return named.expand(e.sql)
+		}
+	}
+
 	simple := true
 	for _, arg := range e.args {
 		if _, ok := arg.(SQLizer); ok {
@@ -42,43 +56,39 @@ func (e expr) SQL() (sql string, args []any, err error) {
 
 	buf := &bytes.Buffer{}
 	ap := e.args
-	sp := e.sql
+	l := newLexer(e.sql)
 
 	var isql string
 	var iargs []any
 
-	for err == nil && len(ap) > 0 && sp != "" {
-		i := strings.Index(sp, "?")
-		if i < 0 {
+	for err == nil && len(ap) > 0 {
+		kind, text := l.next()
+		if kind == tokenEOF {
 			// no more placeholders
 			break
 		}
-		if len(sp) > i+1 && sp[i+1:i+2] == "?" {
-			// escaped "??"; append it and step past
-			buf.WriteString(sp[:i+2])
-			sp = sp[i+2:]
+		if kind != tokenPlaceholder {
+			buf.WriteString(text)
 			continue
 		}
 
 		if as, ok := ap[0].(SQLizer); ok {
 			// sqlizer argument; expand it and append the result
-			isql, iargs, err = as.SQL()
-			buf.WriteString(sp[:i])
+			isql, iargs, err = nestedSQL(as)
 			buf.WriteString(isql)
 			args = append(args, iargs...)
 		} else {
 			// normal argument; append it and the placeholder
-			buf.WriteString(sp[:i+1])
+			buf.WriteString(text)
 			args = append(args, ap[0])
 		}
 
-		// step past the argument and placeholder
+		// step past the argument
 		ap = ap[1:]
-		sp = sp[i+1:]
 	}
 
 	// append the remaining sql and arguments
-	buf.WriteString(sp)
+	buf.WriteString(e.sql[l.pos:])
 	return buf.String(), append(args, ap...), err
 }
 
@@ -94,7 +104,7 @@ func ConcatSQL(ce ...any) (sql string, args []any, err error) {
 		case string:
 			sql += p
 		case SQLizer:
-			pSQL, pArgs, err := p.SQL()
+			pSQL, pArgs, err := nestedSQL(p)
 			if err != nil {
 				return "", nil, err
 			}
@@ -117,9 +127,13 @@ type Alias struct {
 	As   string
 }
 
-// AliasExprSQL returns a SQL query based on the alias.
-func (a Alias) SQL() (sql string, args []any, err error) {
-	sql, args, err = a.Expr.SQL()
+// SQL returns a SQL query based on the alias.
+func (a Alias) SQL() (string, []any, error) {
+	return resolvedSQL(a)
+}
+
+func (a Alias) unfinalizedSQL() (sql string, args []any, err error) {
+	sql, args, err = nestedSQL(a.Expr)
 	if err == nil {
 		sql = fmt.Sprintf("(%s) AS %s", sql, a.As)
 	}
@@ -127,6 +141,13 @@ func (a Alias) SQL() (sql string, args []any, err error) {
 }
 
 // Eq is syntactic sugar for use with Where/Having/Set methods.
+//
+// A SelectBuilder value is rendered as a subquery with IN, or NOT IN for NotEq.
+//
+// Ex:
+//
+//	.Where(Eq{"id": 1}) == "id = 1"
+//	.Where(Eq{"user_id": Select("id").From("users").Where("active")}) == "user_id IN (SELECT id FROM users WHERE active)"
 type Eq map[string]any
 
 func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
@@ -137,11 +158,12 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
 	}
 
 	var (
-		exprs       []string
-		equalOpr    = "="
-		nullOpr     = "IS"
-		inEmptyExpr = sqlFalse
-		inOpr       = "ANY"
+		exprs         []string
+		equalOpr      = "="
+		nullOpr       = "IS"
+		inEmptyExpr   = sqlFalse
+		inOpr         = "ANY"
+		inSubqueryOpr = "IN"
 	)
 
 	if useNotOpr {
@@ -149,6 +171,7 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
 		nullOpr = "IS NOT"
 		inEmptyExpr = sqlTrue
 		inOpr = "ALL"
+		inSubqueryOpr = "NOT IN"
 	}
 
 	sortedKeys := getSortedKeys(eq)
@@ -172,7 +195,16 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
 			}
 		}
 
-		if val == nil {
+		if sub, ok := val.(SelectBuilder); ok {
+			var subSQL string
+			var subArgs []any
+			subSQL, subArgs, err = nestedSQL(sub)
+			if err != nil {
+				return
+			}
+			expr = fmt.Sprintf("%s %s (%s)", key, inSubqueryOpr, subSQL)
+			args = append(args, subArgs...)
+		} else if val == nil {
 			expr = fmt.Sprintf("%s %s NULL", key, nullOpr)
 		} else {
 			if isListType(val) {
@@ -197,7 +229,11 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
 	return
 }
 
-func (eq Eq) SQL() (sql string, args []any, err error) {
+func (eq Eq) SQL() (string, []any, error) {
+	return resolvedSQL(eq)
+}
+
+func (eq Eq) unfinalizedSQL() (sql string, args []any, err error) {
 	return eq.toSQL(false)
 }
 
@@ -207,20 +243,29 @@ func (eq Eq) SQL() (sql string, args []any, err error) {
 //	.Where(NotEq{"id": 1}) == "id <> 1"
 type NotEq Eq
 
-func (neq NotEq) SQL() (sql string, args []any, err error) {
+func (neq NotEq) SQL() (string, []any, error) {
+	return resolvedSQL(neq)
+}
+
+func (neq NotEq) unfinalizedSQL() (sql string, args []any, err error) {
 	return Eq(neq).toSQL(true)
 }
 
 // Like is syntactic sugar for use with LIKE conditions.
+// A slice or array value matches any of the patterns with LIKE ANY, while the
+// negated operators match none of them with ALL.
 // Ex:
 //
 //	.Where(Like{"name": "%irrel"})
+//	.Where(Like{"name": []string{"a%", "b%"}}) == "name LIKE ANY (?)"
+//	.Where(NotLike{"name": []string{"a%", "b%"}}) == "name NOT LIKE ALL (?)"
 type Like map[string]any
 
 func (lk Like) toSQL(opr string) (sql string, args []any, err error) {
 	var exprs []string
-	for key, val := range lk {
+	for _, key := range getSortedKeys(lk) {
 		expr := ""
+		val := lk[key]
 
 		switch v := val.(type) {
 		case Valuer:
@@ -234,8 +279,16 @@ func (lk Like) toSQL(opr string) (sql string, args []any, err error) {
 			return
 		} else {
 			if isListType(val) {
-				err = fmt.Errorf("cannot use array or slice with like operators")
-				return
+				quantifier, emptyExpr := "ANY", sqlFalse
+				if strings.HasPrefix(opr, "NOT ") {
+					quantifier, emptyExpr = "ALL", sqlTrue
+				}
+				if reflect.ValueOf(val).Len() == 0 {
+					expr = emptyExpr
+				} else {
+					expr = fmt.Sprintf("%s %s %s (?)", key, opr, quantifier)
+					args = append(args, val)
+				}
 			} else {
 				expr = fmt.Sprintf("%s %s ?", key, opr)
 				args = append(args, val)
@@ -364,6 +417,274 @@ func (gtOrEq GtOrEq) SQL() (sql string, args []any, err error) {
 	return Lt(gtOrEq).toSQL(true, true)
 }
 
+// mapPredicate builds the predicate of each key of m, in sorted order, and
+// joins them with AND.
+func mapPredicate(m map[string]any, pred func(key string, val any) (string, []any, error)) (sql string, args []any, err error) {
+	exprs := make([]string, 0, len(m))
+	for _, key := range getSortedKeys(m) {
+		val := m[key]
+		if v, ok := val.(Valuer); ok {
+			if val, err = v.Value(); err != nil {
+				return
+			}
+		}
+		expr, exprArgs, err := pred(key, val)
+		if err != nil {
+			return "", nil, err
+		}
+		exprs = append(exprs, expr)
+		args = append(args, exprArgs...)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+// valueSQL returns the SQL of v if it is a SQLizer, or a placeholder bound to v.
+func valueSQL(v any) (string, []any, error) {
+	return nestedSQL(newValuePart(v))
+}
+
+// Between is syntactic sugar for use with BETWEEN conditions.
+// Each value must be a slice or array with the lower and upper bounds, which
+// can be SQLizers.
+// Ex:
+//
+//	.Where(Between{"age": []int{18, 65}}) == "age BETWEEN 18 AND 65"
+type Between map[string]any
+
+func (b Between) toSQL(opr string) (string, []any, error) {
+	return mapPredicate(b, func(key string, val any) (sql string, args []any, err error) {
+		if val == nil {
+			err = fmt.Errorf("cannot use null with between operators")
+			return
+		}
+		r := reflect.ValueOf(val)
+		if !isListType(val) || r.Len() != 2 {
+			err = fmt.Errorf("between operators need a slice or array of 2 values, not %T", val)
+			return
+		}
+		low, lowArgs, err := valueSQL(r.Index(0).Interface())
+		if err != nil {
+			return
+		}
+		high, highArgs, err := valueSQL(r.Index(1).Interface())
+		if err != nil {
+			return
+		}
+		sql = fmt.Sprintf("%s %s %s AND %s", key, opr, low, high)
+		args = append(lowArgs, highArgs...)
+		return
+	})
+}
+
+func (b Between) SQL() (string, []any, error) {
+	return resolvedSQL(b)
+}
+
+func (b Between) unfinalizedSQL() (sql string, args []any, err error) {
+	return b.toSQL("BETWEEN")
+}
+
+// NotBetween is syntactic sugar for use with NOT BETWEEN conditions.
+// Ex:
+//
+//	.Where(NotBetween{"age": []int{18, 65}}) == "age NOT BETWEEN 18 AND 65"
+type NotBetween Between
+
+func (nb NotBetween) SQL() (string, []any, error) {
+	return resolvedSQL(nb)
+}
+
+func (nb NotBetween) unfinalizedSQL() (sql string, args []any, err error) {
+	return Between(nb).toSQL("NOT BETWEEN")
+}
+
+// BetweenSymmetric is syntactic sugar for use with BETWEEN SYMMETRIC
+// conditions, where the bounds can be in any order.
+// Ex:
+//
+//	.Where(BetweenSymmetric{"x": []int{10, 1}}) == "x BETWEEN SYMMETRIC 10 AND 1"
+type BetweenSymmetric Between
+
+func (bs BetweenSymmetric) SQL() (string, []any, error) {
+	return resolvedSQL(bs)
+}
+
+func (bs BetweenSymmetric) unfinalizedSQL() (sql string, args []any, err error) {
+	return Between(bs).toSQL("BETWEEN SYMMETRIC")
+}
+
+// NotBetweenSymmetric is syntactic sugar for use with NOT BETWEEN SYMMETRIC conditions.
+// Ex:
+//
+//	.Where(NotBetweenSymmetric{"x": []int{10, 1}}) == "x NOT BETWEEN SYMMETRIC 10 AND 1"
+type NotBetweenSymmetric Between
+
+func (nbs NotBetweenSymmetric) SQL() (string, []any, error) {
+	return resolvedSQL(nbs)
+}
+
+func (nbs NotBetweenSymmetric) unfinalizedSQL() (sql string, args []any, err error) {
+	return Between(nbs).toSQL("NOT BETWEEN SYMMETRIC")
+}
+
+// IsDistinctFrom is syntactic sugar for use with IS DISTINCT FROM conditions,
+// which compare null values as equal to each other, and different from any
+// other value. A nil value is rendered as NULL, like in Eq.
+// Ex:
+//
+//	.Where(IsDistinctFrom{"parent_id": 1}) == "parent_id IS DISTINCT FROM 1"
+type IsDistinctFrom map[string]any
+
+func (d IsDistinctFrom) toSQL(opr string) (string, []any, error) {
+	return mapPredicate(d, func(key string, val any) (string, []any, error) {
+		if val == nil {
+			return fmt.Sprintf("%s %s NULL", key, opr), nil, nil
+		}
+		sql, args, err := valueSQL(val)
+		return fmt.Sprintf("%s %s %s", key, opr, sql), args, err
+	})
+}
+
+func (d IsDistinctFrom) SQL() (string, []any, error) {
+	return resolvedSQL(d)
+}
+
+func (d IsDistinctFrom) unfinalizedSQL() (sql string, args []any, err error) {
+	return d.toSQL("IS DISTINCT FROM")
+}
+
+// IsNotDistinctFrom is syntactic sugar for use with IS NOT DISTINCT FROM
+// conditions, which is like Eq, but true when both sides are null.
+// Ex:
+//
+//	.Where(IsNotDistinctFrom{"parent_id": nil}) == "parent_id IS NOT DISTINCT FROM NULL"
+type IsNotDistinctFrom IsDistinctFrom
+
+func (nd IsNotDistinctFrom) SQL() (string, []any, error) {
+	return resolvedSQL(nd)
+}
+
+func (nd IsNotDistinctFrom) unfinalizedSQL() (sql string, args []any, err error) {
+	return IsDistinctFrom(nd).toSQL("IS NOT DISTINCT FROM")
+}
+
+// Is is syntactic sugar for use with IS TRUE, IS FALSE, and IS UNKNOWN
+// conditions, for true, false, and nil values.
+// Unlike Eq, they are never null: a null boolean is neither TRUE nor FALSE.
+// Ex:
+//
+//	.Where(Is{"verified": true, "banned": nil}) == "banned IS UNKNOWN AND verified IS TRUE"
+type Is map[string]any
+
+func (is Is) toSQL(opr string) (string, []any, error) {
+	return mapPredicate(is, func(key string, val any) (string, []any, error) {
+		if p, ok := val.(*bool); ok {
+			if p == nil {
+				val = nil
+			} else {
+				val = *p
+			}
+		}
+		switch val {
+		case true:
+			return fmt.Sprintf("%s %s TRUE", key, opr), nil, nil
+		case false:
+			return fmt.Sprintf("%s %s FALSE", key, opr), nil, nil
+		case nil:
+			return fmt.Sprintf("%s %s UNKNOWN", key, opr), nil, nil
+		}
+		return "", nil, fmt.Errorf("cannot use %T with %s TRUE, FALSE, or UNKNOWN; use true, false, or nil", val, opr)
+	})
+}
+
+func (is Is) SQL() (sql string, args []any, err error) {
+	return is.toSQL("IS")
+}
+
+// IsNot is syntactic sugar for use with IS NOT TRUE, IS NOT FALSE, and IS NOT
+// UNKNOWN conditions.
+// Ex:
+//
+//	.Where(IsNot{"verified": true}) == "verified IS NOT TRUE"
+type IsNot Is
+
+func (isNot IsNot) SQL() (sql string, args []any, err error) {
+	return Is(isNot).toSQL("IS NOT")
+}
+
+type not struct {
+	pred SQLizer
+}
+
+// Not negates pred.
+// Ex:
+//
+//	.Where(Not(Or{Eq{"a": 1}, Eq{"b": 2}})) == "NOT ((a = 1 OR b = 2))"
+func Not(pred SQLizer) SQLizer {
+	return not{pred: pred}
+}
+
+func (n not) SQL() (string, []any, error) {
+	return resolvedSQL(n)
+}
+
+func (n not) unfinalizedSQL() (sql string, args []any, err error) {
+	if n.pred != nil {
+		sql, args, err = nestedSQL(n.pred)
+	}
+	if err == nil && sql == "" {
+		err = fmt.Errorf("NOT must have an expression")
+	}
+	if err != nil {
+		return
+	}
+	sql = fmt.Sprintf("NOT (%s)", sql)
+	return
+}
+
+// RowComparison is a row constructor comparison, which compares the columns
+// with the values in order, like sorting by them.
+// Ex:
+//
+//	.Where(RowComparison{Columns: []string{"last_name", "id"}, Op: ">", Values: []any{"Smith", 42}})
+//	// (last_name, id) > ('Smith', 42)
+type RowComparison struct {
+	Columns []string
+	Op      string
+	Values  []any
+}
+
+func (rc RowComparison) SQL() (string, []any, error) {
+	return resolvedSQL(rc)
+}
+
+func (rc RowComparison) unfinalizedSQL() (sql string, args []any, err error) {
+	if len(rc.Columns) == 0 {
+		err = fmt.Errorf("row comparisons must have at least one column")
+		return
+	}
+	if len(rc.Values) != len(rc.Columns) {
+		err = fmt.Errorf("row comparisons must have as many values as columns, got %d and %d", len(rc.Values), len(rc.Columns))
+		return
+	}
+	if !isOperator(rc.Op) {
+		err = fmt.Errorf("invalid row comparison operator %q", rc.Op)
+		return
+	}
+	values := make([]string, len(rc.Values))
+	for i, v := range rc.Values {
+		var vArgs []any
+		values[i], vArgs, err = valueSQL(v)
+		if err != nil {
+			return
+		}
+		args = append(args, vArgs...)
+	}
+	sql = fmt.Sprintf("(%s) %s (%s)", strings.Join(rc.Columns, ", "), rc.Op, strings.Join(values, ", "))
+	return
+}
+
 func join(c []SQLizer, sep, defaultExpr string) (sql string, args []any, err error) {
 	if len(c) == 0 {
 		return defaultExpr, []any{}, nil
@@ -389,6 +710,10 @@ func join(c []SQLizer, sep, defaultExpr string) (sql string, args []any, err err
 type And []SQLizer
 
 func (a And) SQL() (string, []any, error) {
+	return resolvedSQL(a)
+}
+
+func (a And) unfinalizedSQL() (string, []any, error) {
 	return join(a, " AND ", sqlTrue)
 }
 
@@ -396,9 +721,120 @@ func (a And) SQL() (string, []any, error) {
 type Or []SQLizer
 
 func (o Or) SQL() (string, []any, error) {
+	return resolvedSQL(o)
+}
+
+func (o Or) unfinalizedSQL() (string, []any, error) {
 	return join(o, " OR ", sqlFalse)
 }
 
+type subqueryPredicate struct {
+	prefix string
+	op     string
+	sub    SQLizer
+}
+
+func (p subqueryPredicate) SQL() (string, []any, error) {
+	return resolvedSQL(p)
+}
+
+func (p subqueryPredicate) unfinalizedSQL() (sql string, args []any, err error) {
+	if p.sub == nil {
+		err = fmt.Errorf("%s must have a subquery", strings.TrimSpace(p.op))
+		return
+	}
+	sql, args, err = nestedSQL(p.sub)
+	if err != nil {
+		return
+	}
+	sql = fmt.Sprintf("%s%s (%s)", p.prefix, p.op, sql)
+	return
+}
+
+// Exists is the EXISTS (subquery) predicate, which is true if the subquery
+// returns at least one row.
+//
+// Ex:
+//
+//	.Where(Exists(Select("1").From("orders o").Where("o.user_id = u.id")))
+func Exists(sub SQLizer) SQLizer {
+	return subqueryPredicate{op: "EXISTS", sub: sub}
+}
+
+// NotExists is the NOT EXISTS (subquery) predicate.
+//
+// See Exists.
+func NotExists(sub SQLizer) SQLizer {
+	return subqueryPredicate{op: "NOT EXISTS", sub: sub}
+}
+
+// In is the column IN (subquery) predicate.
+// sub can be any SQLizer returning a list of rows, such as a SelectBuilder or
+// an Expr with VALUES.
+//
+// Ex:
+//
+//	.Where(In("user_id", Select("id").From("users").Where("active = ?", true)))
+func In(column string, sub SQLizer) SQLizer {
+	return subqueryPredicate{prefix: column + " ", op: "IN", sub: sub}
+}
+
+// NotIn is the column NOT IN (subquery) predicate.
+//
+// NOT IN is never true if the subquery returns a null, so prefer NotExists
+// if it can.
+func NotIn(column string, sub SQLizer) SQLizer {
+	return subqueryPredicate{prefix: column + " ", op: "NOT IN", sub: sub}
+}
+
+type quantifiedPredicate struct {
+	column     string
+	op         string
+	quantifier string
+	values     any
+}
+
+func (p quantifiedPredicate) SQL() (string, []any, error) {
+	return resolvedSQL(p)
+}
+
+func (p quantifiedPredicate) unfinalizedSQL() (sql string, args []any, err error) {
+	if !isOperator(p.op) {
+		err = fmt.Errorf("invalid %s operator %q", p.quantifier, p.op)
+		return
+	}
+	if p.values == nil {
+		err = fmt.Errorf("%s must have a subquery or an array", p.quantifier)
+		return
+	}
+	sql, args, err = nestedSQL(newValuePart(p.values))
+	if err != nil {
+		return
+	}
+	sql = fmt.Sprintf("%s %s %s (%s)", p.column, p.op, p.quantifier, sql)
+	return
+}
+
+// Any is the column op ANY (values) predicate, which is true if the
+// comparison is true for any of the values.
+// values can be a SQLizer, such as a SelectBuilder, or an array bound as an arg.
+//
+// Ex:
+//
+//	.Where(Any("price", ">", Select("price").From("products").Where("category = ?", "books")))
+//	.Where(Any("tag", "=", []string{"a", "b"}))
+func Any(column, op string, values any) SQLizer {
+	return quantifiedPredicate{column: column, op: op, quantifier: "ANY", values: values}
+}
+
+// All is the column op ALL (values) predicate, which is true if the
+// comparison is true for all of the values.
+//
+// See Any.
+func All(column, op string, values any) SQLizer {
+	return quantifiedPredicate{column: column, op: op, quantifier: "ALL", values: values}
+}
+
 func getSortedKeys(exp map[string]any) []string {
 	sortedKeys := make([]string, 0, len(exp))
 	for k := range exp {
@@ -1,7 +1,9 @@
 package pgq
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -632,6 +634,68 @@ func TestNotILikeSQL(t *testing.T) {
 	}
 }
 
+func TestLikeList(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "like_any",
+			b:        Like{"name": []string{"a%", "b%"}},
+			wantSQL:  "name LIKE ANY (?)",
+			wantArgs: []any{[]string{"a%", "b%"}},
+		},
+		{
+			name:     "not_like_all",
+			b:        NotLike{"name": []string{"a%", "b%"}},
+			wantSQL:  "name NOT LIKE ALL (?)",
+			wantArgs: []any{[]string{"a%", "b%"}},
+		},
+		{
+			name:     "ilike_any",
+			b:        ILike{"name": [1]string{"sq%"}, "email": "%@example.com"},
+			wantSQL:  "email ILIKE ? AND name ILIKE ANY (?)",
+			wantArgs: []any{"%@example.com", [1]string{"sq%"}},
+		},
+		{
+			name:     "not_ilike_all",
+			b:        NotILike{"name": []string{"sq%"}},
+			wantSQL:  "name NOT ILIKE ALL (?)",
+			wantArgs: []any{[]string{"sq%"}},
+		},
+		{
+			name:    "like_empty",
+			b:       Like{"name": []string{}},
+			wantSQL: "(FALSE)",
+		},
+		{
+			name:    "not_like_empty",
+			b:       NotILike{"name": []string{}},
+			wantSQL: "(TRUE)",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
 func TestSQLEqOrder(t *testing.T) {
 	t.Parallel()
 	b := Eq{"a": 1, "b": 2, "c": 3}
@@ -744,6 +808,375 @@ func TestExprRecursion(t *testing.T) {
 	}
 }
 
+func TestExprContext(t *testing.T) {
+	t.Parallel()
+	b := Expr("data ? 'a?' AND /* ? */ x = ? AND y = ?", Expr("lower(?)", "X"), 2)
+	sql, args, err := b.SQL()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if want := "data ? 'a?' AND /* ? */ x = lower(?) AND y = ?"; sql != want {
+		t.Errorf("expected SQL to be %q, got %q instead", want, sql)
+	}
+	if want := []any{"X", 2}; !reflect.DeepEqual(args, want) {
+		t.Errorf("wanted %v, got %v instead", want, args)
+	}
+}
+
+func FuzzExpr(f *testing.F) {
+	f.Add("x = ? AND y = ?")
+	f.Add("data ? 'a' AND y IN (?)")
+	f.Add("'?' || $$?$$ || \"?\" || ? -- ?")
+	f.Add("E'\\'?' ?? ? /* /* ? */ ? */ ?")
+	f.Fuzz(func(t *testing.T, sql string) {
+		if strings.ContainsRune(sql, backRefMarker) {
+			return
+		}
+		n := countPlaceholders(sql)
+		args := make([]any, n)
+		for i := range args {
+			args[i] = Expr("(?)", i)
+		}
+		s, sargs, err := Expr(sql, args...).SQL()
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", sql, err)
+		}
+		if len(sargs) != n {
+			t.Errorf("expected %d args for %q, got %d instead", n, sql, len(sargs))
+		}
+		if got := countPlaceholders(s); got != n {
+			t.Errorf("expected %d placeholders in %q, got %d instead", n, s, got)
+		}
+		for i, arg := range sargs {
+			if arg != i {
+				t.Errorf("expected arg %d to be %d, got %v instead", i, i, arg)
+			}
+		}
+	})
+}
+
+func TestSubqueryPredicates(t *testing.T) {
+	t.Parallel()
+	active := Select("id").From("users").Where("active = ?", true)
+	orders := Select("1").From("orders o").Where("o.user_id = u.id AND o.total > ?", 100)
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "exists",
+			b:        Select("u.name").From("users u").Where("u.country = ?", "NL").Where(Exists(orders)).Limit(5),
+			wantSQL:  "SELECT u.name FROM users u WHERE u.country = $1 AND EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.total > $2) LIMIT 5",
+			wantArgs: []any{"NL", 100},
+		},
+		{
+			name:     "not_exists",
+			b:        Select("u.name").From("users u").Where(NotExists(orders)).Where("u.id > ?", 1),
+			wantSQL:  "SELECT u.name FROM users u WHERE NOT EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.total > $1) AND u.id > $2",
+			wantArgs: []any{100, 1},
+		},
+		{
+			name:     "in",
+			b:        Select("*").From("orders").Where("total > ?", 5).Where(In("user_id", active)),
+			wantSQL:  "SELECT * FROM orders WHERE total > $1 AND user_id IN (SELECT id FROM users WHERE active = $2)",
+			wantArgs: []any{5, true},
+		},
+		{
+			name:     "not_in_values",
+			b:        Select("*").From("orders").Where(NotIn("(kind, status)", Expr("VALUES (?, ?), ('b', 'c')", "a", "b"))),
+			wantSQL:  "SELECT * FROM orders WHERE (kind, status) NOT IN (VALUES ($1, $2), ('b', 'c'))",
+			wantArgs: []any{"a", "b"},
+		},
+		{
+			name:     "any",
+			b:        Select("*").From("products").Where(Any("price", ">", Select("price").From("products").Where("category = ?", "books"))),
+			wantSQL:  "SELECT * FROM products WHERE price > ANY (SELECT price FROM products WHERE category = $1)",
+			wantArgs: []any{"books"},
+		},
+		{
+			name:     "all",
+			b:        Select("*").From("products").Where("stock > ?", 0).Where(All("price", "<=", Select("max_price").From("budgets").Where("team = ?", "a"))),
+			wantSQL:  "SELECT * FROM products WHERE stock > $1 AND price <= ALL (SELECT max_price FROM budgets WHERE team = $2)",
+			wantArgs: []any{0, "a"},
+		},
+		{
+			name:     "any_array",
+			b:        Select("*").From("posts").Where(Any("tag", "=", []string{"go", "sql"})).Where(All("score", "<>", []int{0})),
+			wantSQL:  "SELECT * FROM posts WHERE tag = ANY ($1) AND score <> ALL ($2)",
+			wantArgs: []any{[]string{"go", "sql"}, []int{0}},
+		},
+		{
+			name:     "eq_select",
+			b:        Select("*").From("orders").Where(Eq{"user_id": active, "status": "paid"}).Where("total > ?", 5),
+			wantSQL:  "SELECT * FROM orders WHERE status = $1 AND user_id IN (SELECT id FROM users WHERE active = $2) AND total > $3",
+			wantArgs: []any{"paid", true, 5},
+		},
+		{
+			name:     "not_eq_select",
+			b:        Select("*").From("orders").Where(NotEq{"a": 1, "user_id": &active}),
+			wantSQL:  "SELECT * FROM orders WHERE a <> $1 AND user_id NOT IN (SELECT id FROM users WHERE active = $2)",
+			wantArgs: []any{1, true},
+		},
+		{
+			name:     "eq_union",
+			b:        Delete("sessions").Where(Eq{"user_id": Union(active, Select("id").From("admins").Where("revoked = ?", true))}),
+			wantSQL:  "DELETE FROM sessions WHERE user_id IN (SELECT id FROM users WHERE active = $1 UNION SELECT id FROM admins WHERE revoked = $2)",
+			wantArgs: []any{true, true},
+		},
+		{
+			name:     "nested",
+			b:        Select("*").From("a").Where(Exists(Select("1").From("b").Where(In("b.id", Select("c.id").From("c").Where("c.x = ?", 1))).Where("b.y = ?", 2))),
+			wantSQL:  "SELECT * FROM a WHERE EXISTS (SELECT 1 FROM b WHERE b.id IN (SELECT c.id FROM c WHERE c.x = $1) AND b.y = $2)",
+			wantArgs: []any{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestSubqueryPredicatesErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{"exists_nil", Exists(nil), "EXISTS must have a subquery"},
+		{"not_in_nil", NotIn("id", nil), "NOT IN must have a subquery"},
+		{"in_error", In("id", Select()), "select statements must have at least one result column"},
+		{"any_operator", Any("id", "= 1 OR", Select("1")), `invalid ANY operator "= 1 OR"`},
+		{"all_nil", All("id", "=", nil), "ALL must have a subquery or an array"},
+		{"eq_select_error", Eq{"id": Select()}, "select statements must have at least one result column"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleExists() {
+	sql, args, _ := Select("u.name").
+		From("users u").
+		Where(Exists(Select("1").From("orders o").Where("o.user_id = u.id AND o.total > ?", 100))).
+		Where(Eq{"u.team_id": Select("id").From("teams").Where("name = ?", "core")}).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT u.name FROM users u WHERE EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.total > $1) AND u.team_id IN (SELECT id FROM teams WHERE name = $2)
+	// [100 core]
+}
+
+func TestComparisonPredicates(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "between",
+			b:        Between{"b": [2]int{3, 4}, "a": []any{1, Expr("now()")}},
+			wantSQL:  "a BETWEEN ? AND now() AND b BETWEEN ? AND ?",
+			wantArgs: []any{1, 3, 4},
+		},
+		{
+			name:     "not_between",
+			b:        NotBetween{"age": []int{18, 65}},
+			wantSQL:  "age NOT BETWEEN ? AND ?",
+			wantArgs: []any{18, 65},
+		},
+		{
+			name:     "between_symmetric",
+			b:        BetweenSymmetric{"x": []int{10, 1}},
+			wantSQL:  "x BETWEEN SYMMETRIC ? AND ?",
+			wantArgs: []any{10, 1},
+		},
+		{
+			name:     "not_between_symmetric",
+			b:        NotBetweenSymmetric{"x": []string{"b", "a"}},
+			wantSQL:  "x NOT BETWEEN SYMMETRIC ? AND ?",
+			wantArgs: []any{"b", "a"},
+		},
+		{
+			name:     "is_distinct_from",
+			b:        IsDistinctFrom{"b": nil, "a": 1, "c": Expr("d")},
+			wantSQL:  "a IS DISTINCT FROM ? AND b IS DISTINCT FROM NULL AND c IS DISTINCT FROM d",
+			wantArgs: []any{1},
+		},
+		{
+			name:     "is_not_distinct_from",
+			b:        IsNotDistinctFrom{"parent_id": ptr(2)},
+			wantSQL:  "parent_id IS NOT DISTINCT FROM ?",
+			wantArgs: []any{ptr(2)},
+		},
+		{
+			name:    "is_not_distinct_from_nil",
+			b:       IsNotDistinctFrom{"parent_id": nil},
+			wantSQL: "parent_id IS NOT DISTINCT FROM NULL",
+		},
+		{
+			name:    "is",
+			b:       Is{"verified": true, "banned": nil, "admin": false, "deleted": (*bool)(nil), "active": ptr(true)},
+			wantSQL: "active IS TRUE AND admin IS FALSE AND banned IS UNKNOWN AND deleted IS UNKNOWN AND verified IS TRUE",
+		},
+		{
+			name:    "is_not",
+			b:       IsNot{"verified": true, "banned": nil, "admin": false},
+			wantSQL: "admin IS NOT FALSE AND banned IS NOT UNKNOWN AND verified IS NOT TRUE",
+		},
+		{
+			name:     "not",
+			b:        Not(Or{Eq{"a": 1}, Eq{"b": 2}}),
+			wantSQL:  "NOT ((a = ? OR b = ?))",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "row_comparison",
+			b:        RowComparison{Columns: []string{"last_name", "id"}, Op: ">", Values: []any{"Smith", Expr("?::int", 42)}},
+			wantSQL:  "(last_name, id) > (?, ?::int)",
+			wantArgs: []any{"Smith", 42},
+		},
+		{
+			name:     "like_sorted",
+			b:        Like{"name": "a%", "email": "%@example.com"},
+			wantSQL:  "email LIKE ? AND name LIKE ?",
+			wantArgs: []any{"%@example.com", "a%"},
+		},
+		{
+			name: "select",
+			b: Select("*").From("users").
+				Where("active = ?", true).
+				Where(Between{"age": []int{18, 65}}).
+				Where(Not(Eq{"country": "NL"})).
+				Where(RowComparison{Columns: []string{"a", "b"}, Op: "<=", Values: []any{1, 2}}),
+			wantSQL:  "SELECT * FROM users WHERE active = $1 AND age BETWEEN $2 AND $3 AND NOT (country = $4) AND (a, b) <= ($5, $6)",
+			wantArgs: []any{true, 18, 65, "NL", 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestComparisonPredicatesErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "between_null",
+			b:    Between{"a": nil},
+			want: "cannot use null with between operators",
+		},
+		{
+			name: "between_scalar",
+			b:    NotBetween{"a": 1},
+			want: "between operators need a slice or array of 2 values, not int",
+		},
+		{
+			name: "between_length",
+			b:    BetweenSymmetric{"a": []int{1, 2, 3}},
+			want: "between operators need a slice or array of 2 values, not []int",
+		},
+		{
+			name: "is_value",
+			b:    Is{"a": 1},
+			want: "cannot use int with IS TRUE, FALSE, or UNKNOWN; use true, false, or nil",
+		},
+		{
+			name: "not_nil",
+			b:    Not(nil),
+			want: "NOT must have an expression",
+		},
+		{
+			name: "not_empty",
+			b:    Not(Expr("")),
+			want: "NOT must have an expression",
+		},
+		{
+			name: "row_comparison_no_columns",
+			b:    RowComparison{Op: "="},
+			want: "row comparisons must have at least one column",
+		},
+		{
+			name: "row_comparison_values",
+			b:    RowComparison{Columns: []string{"a", "b"}, Op: "=", Values: []any{1}},
+			want: "row comparisons must have as many values as columns, got 1 and 2",
+		},
+		{
+			name: "row_comparison_operator",
+			b:    RowComparison{Columns: []string{"a"}, Op: "; DROP", Values: []any{1}},
+			want: `invalid row comparison operator "; DROP"`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleBetween() {
+	sql, args, _ := Select("name").
+		From("users").
+		Where(Between{"age": []int{18, 65}}).
+		Where(IsDistinctFrom{"team_id": nil}).
+		Where(Is{"verified": true}).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT name FROM users WHERE age BETWEEN $1 AND $2 AND team_id IS DISTINCT FROM NULL AND verified IS TRUE
+	// [18 65]
+}
+
 func ExampleEq() {
 	Select("id", "created", "first_name").From("users").Where(Eq{
 		"company": 20,
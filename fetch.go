@@ -0,0 +1,105 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// fetchClause is a FETCH {FIRST|NEXT} n {ROW|ROWS} {ONLY|WITH TIES} clause.
+type fetchClause struct {
+	next     bool
+	count    SQLizer
+	one      bool
+	withTies bool
+}
+
+func newFetchClause(next bool, count any) *fetchClause {
+	f := &fetchClause{next: next, count: newValuePart(count)}
+	if _, ok := count.(SQLizer); !ok {
+		f.one = fmt.Sprint(count) == "1"
+	}
+	return f
+}
+
+func (f fetchClause) appendToSQL(sql *bytes.Buffer, args []any) ([]any, error) {
+	if f.next {
+		sql.WriteString(" FETCH NEXT ")
+	} else {
+		sql.WriteString(" FETCH FIRST ")
+	}
+	args, err := appendSQL([]SQLizer{f.count}, sql, "", args)
+	if err != nil {
+		return nil, err
+	}
+	if f.one {
+		sql.WriteString(" ROW")
+	} else {
+		sql.WriteString(" ROWS")
+	}
+	if f.withTies {
+		sql.WriteString(" WITH TIES")
+	} else {
+		sql.WriteString(" ONLY")
+	}
+	return args, nil
+}
+
+// validateFetch checks the FETCH clause against the clauses PostgreSQL
+// doesn't allow it to be combined with.
+func (b SelectBuilder) validateFetch() error {
+	if b.fetch == nil {
+		return nil
+	}
+	if b.fetch.count == nil {
+		return errors.New("WITH TIES requires FETCH FIRST or FETCH NEXT")
+	}
+	if b.limit != nil {
+		return errors.New("select statements cannot have both LIMIT and FETCH")
+	}
+	if !b.fetch.withTies {
+		return nil
+	}
+	if len(b.orderByParts) == 0 {
+		return errors.New("WITH TIES cannot be specified without ORDER BY clause")
+	}
+	for _, l := range b.locks {
+		if l.skipLocked {
+			return errors.New("SKIP LOCKED and WITH TIES options cannot be used together")
+		}
+	}
+	return nil
+}
+
+// FetchFirst sets a FETCH FIRST count ROWS ONLY clause on the query, the SQL
+// standard spelling of LIMIT, which can also include ties with WithTies.
+// count is bound as an arg, unless it is a SQLizer.
+//
+// Ex:
+//
+//	Select("*").From("scores").OrderBy("points DESC").FetchFirst(3).WithTies()
+//	// SELECT * FROM scores ORDER BY points DESC FETCH FIRST $1 ROWS WITH TIES
+func (b SelectBuilder) FetchFirst(count any) SelectBuilder {
+	b.fetch = newFetchClause(false, count)
+	return b
+}
+
+// FetchNext sets a FETCH NEXT count ROWS ONLY clause on the query, which is
+// the same as FetchFirst, but reads better after an OFFSET.
+func (b SelectBuilder) FetchNext(count any) SelectBuilder {
+	b.fetch = newFetchClause(true, count)
+	return b
+}
+
+// WithTies changes the FETCH clause of the query to also return the rows that
+// tie for the last place in the ORDER BY, which the query must have.
+// It must be called after FetchFirst or FetchNext.
+func (b SelectBuilder) WithTies() SelectBuilder {
+	fetch := fetchClause{}
+	if b.fetch != nil {
+		fetch = *b.fetch
+	}
+	fetch.withTies = true
+	b.fetch = &fetch
+	return b
+}
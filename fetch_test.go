@@ -0,0 +1,167 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuilderPagination(t *testing.T) {
+	t.Parallel()
+	scores := Select("name", "points").From("scores").Where("game = ?", "chess")
+
+	testCases := []struct {
+		name     string
+		b        SelectBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "limit_offset",
+			b:        scores.Limit(10).Offset(20),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 LIMIT 10 OFFSET 20",
+			wantArgs: []any{"chess"},
+		},
+		{
+			name:     "limit_offset_expr",
+			b:        scores.OrderBy("points DESC").LimitExpr(10).OffsetExpr(20),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 ORDER BY points DESC LIMIT $2 OFFSET $3",
+			wantArgs: []any{"chess", 10, 20},
+		},
+		{
+			name:     "limit_sqlizer",
+			b:        scores.LimitExpr(Expr("least(?, 100)", 500)).OffsetExpr(Expr("? * ?", 3, 10)),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 LIMIT least($2, 100) OFFSET $3 * $4",
+			wantArgs: []any{"chess", 500, 3, 10},
+		},
+		{
+			name:     "limit_all",
+			b:        scores.LimitAll().OffsetExpr(5),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 LIMIT ALL OFFSET $2",
+			wantArgs: []any{"chess", 5},
+		},
+		{
+			name:     "fetch_first",
+			b:        scores.OrderBy("points DESC").FetchFirst(3),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 ORDER BY points DESC FETCH FIRST $2 ROWS ONLY",
+			wantArgs: []any{"chess", 3},
+		},
+		{
+			name:     "fetch_first_row",
+			b:        scores.FetchFirst(1),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 FETCH FIRST $2 ROW ONLY",
+			wantArgs: []any{"chess", 1},
+		},
+		{
+			name:     "fetch_next_with_ties",
+			b:        scores.OrderBy("points DESC").OffsetExpr(10).FetchNext(Expr("?::int", "5")).WithTies(),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 ORDER BY points DESC OFFSET $2 FETCH NEXT $3::int ROWS WITH TIES",
+			wantArgs: []any{"chess", 10, "5"},
+		},
+		{
+			name:     "fetch_lock",
+			b:        scores.OrderBy("points DESC").FetchFirst(2).WithTies().ForUpdate().NoWait(),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 ORDER BY points DESC FETCH FIRST $2 ROWS WITH TIES FOR UPDATE NOWAIT",
+			wantArgs: []any{"chess", 2},
+		},
+		{
+			name:     "remove_limit",
+			b:        scores.FetchFirst(2).RemoveLimit().LimitExpr(4),
+			wantSQL:  "SELECT name, points FROM scores WHERE game = $1 LIMIT $2",
+			wantArgs: []any{"chess", 4},
+		},
+		{
+			name: "union",
+			b:    scores.OrderBy("points").FetchFirst(1).Union(scores.OrderBy("points DESC").FetchFirst(1)).LimitExpr(2),
+			wantSQL: "(SELECT name, points FROM scores WHERE game = $1 ORDER BY points FETCH FIRST $2 ROW ONLY) UNION " +
+				"(SELECT name, points FROM scores WHERE game = $3 ORDER BY points DESC FETCH FIRST $4 ROW ONLY) LIMIT $5",
+			wantArgs: []any{"chess", 1, "chess", 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestSelectBuilderPaginationImmutable(t *testing.T) {
+	t.Parallel()
+	b := Select("*").From("t").OrderBy("a").FetchFirst(5)
+	_ = b.WithTies()
+	if sql, _ := b.MustSQL(); sql != "SELECT * FROM t ORDER BY a FETCH FIRST $1 ROWS ONLY" {
+		t.Errorf("unexpected SQL: %q", sql)
+	}
+}
+
+func TestSelectBuilderPaginationErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SelectBuilder
+		want string
+	}{
+		{
+			name: "with_ties_no_order_by",
+			b:    Select("*").From("t").FetchFirst(5).WithTies(),
+			want: "WITH TIES cannot be specified without ORDER BY clause",
+		},
+		{
+			name: "with_ties_no_fetch",
+			b:    Select("*").From("t").OrderBy("a").WithTies(),
+			want: "WITH TIES requires FETCH FIRST or FETCH NEXT",
+		},
+		{
+			name: "with_ties_skip_locked",
+			b:    Select("*").From("t").OrderBy("a").FetchFirst(5).WithTies().ForUpdate().SkipLocked(),
+			want: "SKIP LOCKED and WITH TIES options cannot be used together",
+		},
+		{
+			name: "limit_fetch",
+			b:    Select("*").From("t").Limit(5).FetchFirst(5),
+			want: "select statements cannot have both LIMIT and FETCH",
+		},
+		{
+			name: "limit_expr",
+			b:    Select("*").From("t").LimitExpr(Expr("?", Named{"a": 1})),
+			want: "positional placeholders cannot be used with named parameters in \"?\"",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleSelectBuilder_FetchFirst() {
+	sql, args, _ := Select("name", "points").
+		From("scores").
+		Where("game = ?", "chess").
+		OrderBy("points DESC").
+		FetchFirst(3).
+		WithTies().
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT name, points FROM scores WHERE game = $1 ORDER BY points DESC FETCH FIRST $2 ROWS WITH TIES
+	// [chess 3]
+}
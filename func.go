@@ -0,0 +1,282 @@
+package pgq
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type funcCall struct {
+	name     string
+	args     []any
+	variadic bool
+}
+
+// Func is a call to the SQL function name with the args, which are bound as
+// args, unless they are SQLizers, such as Expr for a column.
+// name can be schema-qualified, and isn't quoted.
+//
+// Ex:
+//
+//	Func("lower", Expr("email")) == "lower(email)"
+//	Func("date_trunc", "day", Expr("created_at")) == "date_trunc(?, created_at)"
+func Func(name string, args ...any) SQLizer {
+	return funcCall{name: name, args: args}
+}
+
+func (f funcCall) SQL() (string, []any, error) {
+	return resolvedSQL(f)
+}
+
+func (f funcCall) unfinalizedSQL() (sql string, args []any, err error) {
+	if !isQualifiedName(f.name) {
+		err = fmt.Errorf("invalid function name %q", f.name)
+		return
+	}
+	if f.variadic && len(f.args) == 0 {
+		err = fmt.Errorf("%s must have at least one argument", f.name)
+		return
+	}
+	parts := make([]SQLizer, len(f.args))
+	for i, arg := range f.args {
+		parts[i] = newValuePart(arg)
+	}
+	return callSQL(f.name, parts)
+}
+
+// callSQL returns the call to the function name with the parts as arguments.
+func callSQL(name string, parts []SQLizer) (sql string, args []any, err error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(name)
+	buf.WriteString("(")
+	if args, err = appendSQL(parts, buf, ", ", nil); err != nil {
+		return
+	}
+	buf.WriteString(")")
+	sql = buf.String()
+	return
+}
+
+// isQualifiedName reports whether s is an unquoted, optionally
+// schema-qualified, name of a type or function.
+func isQualifiedName(s string) bool {
+	for _, name := range strings.Split(s, ".") {
+		if name == "" || !isIdentStart(name[0]) {
+			return false
+		}
+		for i := 1; i < len(name); i++ {
+			if !isIdentChar(name[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Coalesce is the COALESCE function, which returns the first of the args that
+// isn't null.
+//
+// Ex:
+//
+//	Coalesce(Expr("nickname"), Expr("name"), "anonymous") == "coalesce(nickname, name, ?)"
+func Coalesce(args ...any) SQLizer {
+	return funcCall{name: "coalesce", args: args, variadic: true}
+}
+
+// NullIf is the NULLIF function, which returns null if a equals b, or a
+// otherwise.
+//
+// Ex:
+//
+//	NullIf(Expr("name"), "") == "nullif(name, ?)"
+func NullIf(a, b any) SQLizer {
+	return funcCall{name: "nullif", args: []any{a, b}}
+}
+
+// Greatest is the GREATEST function, which returns the largest of the args,
+// ignoring nulls.
+//
+// Ex:
+//
+//	Greatest(Expr("price"), 10) == "greatest(price, ?)"
+func Greatest(args ...any) SQLizer {
+	return funcCall{name: "greatest", args: args, variadic: true}
+}
+
+// Least is the LEAST function, which returns the smallest of the args,
+// ignoring nulls.
+//
+// Ex:
+//
+//	Least(Expr("price"), 100) == "least(price, ?)"
+func Least(args ...any) SQLizer {
+	return funcCall{name: "least", args: args, variadic: true}
+}
+
+// Now is the now() function, which returns the start time of the current
+// transaction.
+func Now() SQLizer {
+	return funcCall{name: "now"}
+}
+
+type cast struct {
+	expr  any
+	typ   string
+	typed bool
+}
+
+// Cast is the CAST(expr AS typ) expression, which converts expr to the type.
+// expr is bound as an arg, unless it is a SQLizer.
+//
+// Ex:
+//
+//	Cast(Expr("price"), "numeric(10, 2)") == "CAST(price AS numeric(10, 2))"
+func Cast(expr any, typ string) SQLizer {
+	return cast{expr: expr, typ: typ}
+}
+
+// Typed is an arg annotated with its type, which renders as ?::typ, and
+// is useful when PostgreSQL can't infer the type of the parameter.
+// A SQLizer value is wrapped in parentheses.
+//
+// Ex:
+//
+//	.Where(Any("id", "=", Typed(ids, "uuid[]"))) == "id = ANY (?::uuid[])"
+func Typed(value any, typ string) SQLizer {
+	return cast{expr: value, typ: typ, typed: true}
+}
+
+func (c cast) SQL() (string, []any, error) {
+	return resolvedSQL(c)
+}
+
+func (c cast) unfinalizedSQL() (sql string, args []any, err error) {
+	if !isTypeName(c.typ) {
+		err = fmt.Errorf("invalid type %q", c.typ)
+		return
+	}
+	sql, args, err = nestedSQL(newValuePart(c.expr))
+	if err != nil {
+		return
+	}
+	if sql == "" {
+		err = fmt.Errorf("cannot cast an empty expression to %s", c.typ)
+		return
+	}
+	switch {
+	case !c.typed:
+		sql = fmt.Sprintf("CAST(%s AS %s)", sql, c.typ)
+	case sql == "?":
+		sql = "?::" + c.typ
+	default:
+		sql = fmt.Sprintf("(%s)::%s", sql, c.typ)
+	}
+	return
+}
+
+// multiWordTypes are the built-in type names with more than one word, and the
+// time zone suffix of time and timestamp, which can follow their modifier.
+var multiWordTypes = []string{"double precision", "character varying", "bit varying"}
+
+// isTypeName reports whether typ is a type name: an optionally
+// schema-qualified name, such as "uuid" or `"MyType"`, or a multi-word
+// built-in name, such as "double precision", followed by an optional
+// modifier, such as "(10, 2)", an optional time zone for time and timestamp,
+// and optional array suffixes, such as "[]".
+func isTypeName(typ string) bool {
+	rest, base := parseTypeBase(typ)
+	if base == "" {
+		return false
+	}
+	rest = parseTypeModifier(rest)
+	if base == "time" || base == "timestamp" {
+		for _, tz := range []string{" with time zone", " without time zone"} {
+			if len(rest) >= len(tz) && strings.EqualFold(rest[:len(tz)], tz) {
+				rest = rest[len(tz):]
+				break
+			}
+		}
+	}
+	for strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end == -1 || !isDigits(rest[1:end]) && end != 1 {
+			return false
+		}
+		rest = rest[end+1:]
+	}
+	return rest == ""
+}
+
+// parseTypeBase returns the rest of typ after its name, and the name in lower
+// case if it is unquoted, or "" if there is no valid name.
+func parseTypeBase(typ string) (rest, base string) {
+	for _, name := range multiWordTypes {
+		if len(typ) >= len(name) && strings.EqualFold(typ[:len(name)], name) {
+			return typ[len(name):], name
+		}
+	}
+	rest = typ
+	for {
+		var n int
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			for n = 1; n < len(rest); n++ {
+				if rest[n] == '"' {
+					if n+1 < len(rest) && rest[n+1] == '"' {
+						n++
+						continue
+					}
+					break
+				}
+			}
+			if n >= len(rest) || n == 1 {
+				return "", ""
+			}
+			n++
+		case rest != "" && isIdentStart(rest[0]):
+			for n = 1; n < len(rest) && isIdentChar(rest[n]); n++ {
+			}
+		default:
+			return "", ""
+		}
+		base, rest = rest[:n], rest[n:]
+		if !strings.HasPrefix(rest, ".") {
+			return rest, strings.ToLower(base)
+		}
+		rest = rest[1:]
+	}
+}
+
+// parseTypeModifier returns the rest of typ after an optional modifier with
+// one or two numbers, such as "(3)" or "(10, 2)".
+func parseTypeModifier(rest string) string {
+	if !strings.HasPrefix(rest, "(") {
+		return rest
+	}
+	end := strings.IndexByte(rest, ')')
+	if end == -1 {
+		return rest
+	}
+	numbers := strings.Split(rest[1:end], ",")
+	if len(numbers) > 2 {
+		return rest
+	}
+	for _, n := range numbers {
+		if !isDigits(strings.TrimSpace(n)) {
+			return rest
+		}
+	}
+	return rest[end+1:]
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFunc(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "func",
+			b:        Func("date_trunc", "day", Expr("created_at")),
+			wantSQL:  "date_trunc(?, created_at)",
+			wantArgs: []any{"day"},
+		},
+		{
+			name:    "func_no_args",
+			b:       Func("pg_catalog.gen_random_uuid"),
+			wantSQL: "pg_catalog.gen_random_uuid()",
+		},
+		{
+			name:     "func_nested",
+			b:        Func("lower", Func("trim", Expr("?", " A "))),
+			wantSQL:  "lower(trim(?))",
+			wantArgs: []any{" A "},
+		},
+		{
+			name:     "coalesce",
+			b:        Coalesce(Expr("nickname"), Expr("name"), "anonymous"),
+			wantSQL:  "coalesce(nickname, name, ?)",
+			wantArgs: []any{"anonymous"},
+		},
+		{
+			name:     "nullif",
+			b:        NullIf(Expr("name"), ""),
+			wantSQL:  "nullif(name, ?)",
+			wantArgs: []any{""},
+		},
+		{
+			name:     "greatest_least",
+			b:        Least(Greatest(Expr("price"), 10), 100),
+			wantSQL:  "least(greatest(price, ?), ?)",
+			wantArgs: []any{10, 100},
+		},
+		{
+			name:    "now",
+			b:       Now(),
+			wantSQL: "now()",
+		},
+		{
+			name:    "cast",
+			b:       Cast(Expr("price"), "numeric(10, 2)"),
+			wantSQL: "CAST(price AS numeric(10, 2))",
+		},
+		{
+			name:     "cast_value",
+			b:        Cast("2024-01-01", "timestamp with time zone"),
+			wantSQL:  "CAST(? AS timestamp with time zone)",
+			wantArgs: []any{"2024-01-01"},
+		},
+		{
+			name:    "cast_types",
+			b:       And{Cast(Expr("a"), "double precision"), Cast(Expr("b"), "timestamp(3) with time zone"), Cast(Expr("c"), `public."My ""Type"""[][3]`), Cast(Expr("d"), "character varying(10)[]")},
+			wantSQL: `(CAST(a AS double precision) AND CAST(b AS timestamp(3) with time zone) AND CAST(c AS public."My ""Type"""[][3]) AND CAST(d AS character varying(10)[]))`,
+		},
+		{
+			name:     "typed",
+			b:        Typed([]string{"a", "b"}, "uuid[]"),
+			wantSQL:  "?::uuid[]",
+			wantArgs: []any{[]string{"a", "b"}},
+		},
+		{
+			name:     "typed_sqlizer",
+			b:        Typed(Expr("? || ?", "a", "b"), `"Label"`),
+			wantSQL:  `(? || ?)::"Label"`,
+			wantArgs: []any{"a", "b"},
+		},
+		{
+			name:     "typed_nil",
+			b:        Coalesce(Typed(nil, "int"), 0),
+			wantSQL:  "coalesce(?::int, ?)",
+			wantArgs: []any{nil, 0},
+		},
+		{
+			name: "select",
+			b: Select("id").
+				Column(Alias{Expr: Coalesce(Expr("nickname"), Expr("name")), As: "display_name"}).
+				From("users").
+				Where(Any("id", "=", Typed([]string{"a", "b"}, "uuid[]"))).
+				Where(Expr("? < ?", Expr("created_at"), Func("date_trunc", "day", Now()))),
+			wantSQL:  "SELECT id, (coalesce(nickname, name)) AS display_name FROM users WHERE id = ANY ($1::uuid[]) AND created_at < date_trunc($2, now())",
+			wantArgs: []any{[]string{"a", "b"}, "day"},
+		},
+		{
+			name: "update",
+			b: Update("products").
+				Set("price", Greatest(Cast(Expr("price * ?", 0.9), "numeric(10, 2)"), 1)).
+				Set("updated_at", Now()).
+				Where(Eq{"id": 1}),
+			wantSQL:  "UPDATE products SET price = greatest(CAST(price * $1 AS numeric(10, 2)), $2), updated_at = now() WHERE id = $3",
+			wantArgs: []any{0.9, 1, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestFuncErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "func_name",
+			b:    Func("now(); DROP TABLE users; --"),
+			want: `invalid function name "now(); DROP TABLE users; --"`,
+		},
+		{
+			name: "func_empty",
+			b:    Func(""),
+			want: `invalid function name ""`,
+		},
+		{
+			name: "coalesce_no_args",
+			b:    Coalesce(),
+			want: "coalesce must have at least one argument",
+		},
+		{
+			name: "func_arg",
+			b:    Func("lower", Expr("?", Named{"a": 1})),
+			want: "positional placeholders cannot be used with named parameters in \"?\"",
+		},
+		{
+			name: "cast_type",
+			b:    Cast(1, "int) --"),
+			want: `invalid type "int) --"`,
+		},
+		{
+			name: "typed_type",
+			b:    Typed(1, `"int`),
+			want: `invalid type "\"int"`,
+		},
+		{
+			name: "cast_type_union",
+			b:    Cast(1, "text) UNION SELECT password FROM users WHERE (true"),
+			want: `invalid type "text) UNION SELECT password FROM users WHERE (true"`,
+		},
+		{
+			name: "typed_type_or",
+			b:    Typed(1, "int) OR (true"),
+			want: `invalid type "int) OR (true"`,
+		},
+		{
+			name: "cast_type_modifier",
+			b:    Cast(1, "numeric(10, 2, 3)"),
+			want: `invalid type "numeric(10, 2, 3)"`,
+		},
+		{
+			name: "cast_type_comment",
+			b:    Cast(1, "int[]--"),
+			want: `invalid type "int[]--"`,
+		},
+		{
+			name: "cast_type_words",
+			b:    Cast(1, "int or true"),
+			want: `invalid type "int or true"`,
+		},
+		{
+			name: "cast_empty",
+			b:    Cast(Expr(""), "int"),
+			want: "cannot cast an empty expression to int",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleFunc() {
+	sql, args, _ := Select("id").
+		Column(Alias{Expr: Func("date_trunc", "day", Expr("created_at")), As: "day"}).
+		From("orders").
+		Where(Expr("? > ?", Coalesce(Expr("discount"), 0), Typed("5", "int"))).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, (date_trunc($1, created_at)) AS day FROM orders WHERE coalesce(discount, $2) > $3::int
+	// [day 0 5]
+}
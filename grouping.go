@@ -0,0 +1,95 @@
+package pgq
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// groupingElement is a ROLLUP, CUBE, or GROUPING SETS element of GROUP BY,
+// a parenthesized grouping set, or a GROUPING call.
+type groupingElement struct {
+	name  string
+	open  string
+	exprs []SQLizer
+}
+
+func newGroupingElement(name, open string, exprs []any) groupingElement {
+	g := groupingElement{name: name, open: open}
+	for _, expr := range exprs {
+		g.exprs = append(g.exprs, newPart(expr))
+	}
+	return g
+}
+
+func (g groupingElement) SQL() (string, []any, error) {
+	return resolvedSQL(g)
+}
+
+func (g groupingElement) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	// Only the empty grouping set () has no name.
+	if len(g.exprs) == 0 && g.name != "" {
+		err = fmt.Errorf("%s must have at least one expression", g.name)
+		return
+	}
+	sql := &bytes.Buffer{}
+	sql.WriteString(g.open)
+	args, err = appendSQL(g.exprs, sql, ", ", args)
+	if err != nil {
+		return
+	}
+	sql.WriteString(")")
+	sqlStr = sql.String()
+	return
+}
+
+// GroupingSet returns a parenthesized list of expressions, to group by them
+// together inside of ROLLUP, CUBE, or GROUPING SETS.
+// Without expressions, it is the empty grouping set (), for the grand total.
+// Each expression can be a string or a SQLizer.
+//
+// Ex:
+//
+//	Select("a", "b", "c", "sum(x)").From("t").GroupByRollup("a", GroupingSet("b", "c"))
+//	// SELECT a, b, c, sum(x) FROM t GROUP BY ROLLUP (a, (b, c))
+func GroupingSet(exprs ...any) SQLizer {
+	return newGroupingElement("", "(", exprs)
+}
+
+// Grouping returns a GROUPING(exprs...) call, whose bits tell which of the
+// GROUP BY expressions are not included in the grouping set of a row.
+// Each expression can be a string or a SQLizer.
+func Grouping(exprs ...any) SQLizer {
+	return newGroupingElement("GROUPING", "GROUPING(", exprs)
+}
+
+// GroupByRollup adds a ROLLUP element to the GROUP BY clause of the query,
+// grouping by each prefix of exprs, from all of them to none, for subtotals.
+// Each expression can be a string or a SQLizer, such as GroupingSet.
+//
+// Ex:
+//
+//	Select("year", "month", "sum(amount)").From("sales").GroupByRollup("year", "month")
+//	// SELECT year, month, sum(amount) FROM sales GROUP BY ROLLUP (year, month)
+func (b SelectBuilder) GroupByRollup(exprs ...any) SelectBuilder {
+	return b.GroupByClause(newGroupingElement("ROLLUP", "ROLLUP (", exprs))
+}
+
+// GroupByCube adds a CUBE element to the GROUP BY clause of the query,
+// grouping by every subset of exprs.
+// Each expression can be a string or a SQLizer, such as GroupingSet.
+func (b SelectBuilder) GroupByCube(exprs ...any) SelectBuilder {
+	return b.GroupByClause(newGroupingElement("CUBE", "CUBE (", exprs))
+}
+
+// GroupByGroupingSets adds a GROUPING SETS element to the GROUP BY clause of
+// the query, grouping by each of the sets.
+// Each set can be a string, such as a column name, or a SQLizer, such as GroupingSet.
+//
+// Ex:
+//
+//	Select("brand", "size", "sum(sales)").From("items").
+//		GroupByGroupingSets(GroupingSet("brand"), GroupingSet("size"), GroupingSet())
+//	// SELECT brand, size, sum(sales) FROM items GROUP BY GROUPING SETS ((brand), (size), ())
+func (b SelectBuilder) GroupByGroupingSets(sets ...any) SelectBuilder {
+	return b.GroupByClause(newGroupingElement("GROUPING SETS", "GROUPING SETS (", sets))
+}
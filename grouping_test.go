@@ -0,0 +1,153 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+	sales := Select("year", "month", "sum(amount)").From("sales").Where("region = ?", "eu")
+
+	testCases := []struct {
+		name     string
+		b        SelectBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "group_by",
+			b:        sales.GroupBy("year", "month"),
+			wantSQL:  "SELECT year, month, sum(amount) FROM sales WHERE region = $1 GROUP BY year, month",
+			wantArgs: []any{"eu"},
+		},
+		{
+			name:     "clause_args",
+			b:        sales.GroupByClause("date_trunc(?, created_at)", "month").Having("sum(amount) > ?", 10),
+			wantSQL:  "SELECT year, month, sum(amount) FROM sales WHERE region = $1 GROUP BY date_trunc($2, created_at) HAVING sum(amount) > $3",
+			wantArgs: []any{"eu", "month", 10},
+		},
+		{
+			name:     "rollup",
+			b:        sales.GroupByRollup("year", "month"),
+			wantSQL:  "SELECT year, month, sum(amount) FROM sales WHERE region = $1 GROUP BY ROLLUP (year, month)",
+			wantArgs: []any{"eu"},
+		},
+		{
+			name:     "rollup_composite",
+			b:        sales.GroupBy("region").GroupByRollup("year", GroupingSet("month", Expr("extract(day FROM ?::date)", "2024-01-01"))),
+			wantSQL:  "SELECT year, month, sum(amount) FROM sales WHERE region = $1 GROUP BY region, ROLLUP (year, (month, extract(day FROM $2::date)))",
+			wantArgs: []any{"eu", "2024-01-01"},
+		},
+		{
+			name:     "cube",
+			b:        sales.GroupByCube("year", "month"),
+			wantSQL:  "SELECT year, month, sum(amount) FROM sales WHERE region = $1 GROUP BY CUBE (year, month)",
+			wantArgs: []any{"eu"},
+		},
+		{
+			name:     "grouping_sets",
+			b:        sales.GroupByGroupingSets(GroupingSet("year", "month"), "year", GroupingSet()),
+			wantSQL:  "SELECT year, month, sum(amount) FROM sales WHERE region = $1 GROUP BY GROUPING SETS ((year, month), year, ())",
+			wantArgs: []any{"eu"},
+		},
+		{
+			name: "grouping",
+			b: Select("year", "month").
+				Column(Alias{Expr: Grouping("year", "month"), As: "level"}).
+				Column(Agg("sum", "amount")).
+				From("sales").
+				GroupByRollup("year", "month").
+				OrderByClause(Grouping("year", "month")).
+				Limit(10),
+			wantSQL: "SELECT year, month, (GROUPING(year, month)) AS level, sum(amount) FROM sales " +
+				"GROUP BY ROLLUP (year, month) ORDER BY GROUPING(year, month) LIMIT 10",
+		},
+		{
+			name: "having_grouping",
+			b: Select("year", "sum(amount)").From("sales").
+				GroupByCube("year").
+				Having(Expr("? = ?", Grouping("year"), 0)),
+			wantSQL:  "SELECT year, sum(amount) FROM sales GROUP BY CUBE (year) HAVING GROUPING(year) = $1",
+			wantArgs: []any{0},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestGroupByErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "rollup",
+			b:    Select("a").From("t").GroupByRollup(),
+			want: "ROLLUP must have at least one expression",
+		},
+		{
+			name: "cube",
+			b:    Select("a").From("t").GroupByCube(),
+			want: "CUBE must have at least one expression",
+		},
+		{
+			name: "grouping_sets",
+			b:    Select("a").From("t").GroupByGroupingSets(),
+			want: "GROUPING SETS must have at least one expression",
+		},
+		{
+			name: "grouping",
+			b:    Grouping(),
+			want: "GROUPING must have at least one expression",
+		},
+		{
+			name: "invalid",
+			b:    Select("a").From("t").GroupByRollup(1),
+			want: "expected string or SQLizer, not int",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleSelectBuilder_GroupByRollup() {
+	sql, args, _ := Select("year", "month").
+		Column(Grouping("year", "month")).
+		Column(Agg("sum", "amount")).
+		From("sales").
+		Where("region = ?", "eu").
+		GroupByRollup("year", "month").
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT year, month, GROUPING(year, month), sum(amount) FROM sales WHERE region = $1 GROUP BY ROLLUP (year, month)
+	// [eu]
+}
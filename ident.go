@@ -0,0 +1,94 @@
+package pgq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// QuoteIdent quotes name as a PostgreSQL identifier, so it can be safely
+// used as a table or column name in a query, even if it comes from user input.
+//
+// name is split on dots, and each part is quoted separately, so
+// schema-qualified names are kept as such. Double quotes inside a part are
+// escaped, and a "*" part is kept unquoted.
+//
+// Quoted identifiers are case-sensitive: QuoteIdent("Users") refers to a
+// different table than Users.
+//
+// Ex:
+//
+//	QuoteIdent("public.users.id") // "public"."users"."id"
+//	QuoteIdent("u.*")             // "u".*
+func QuoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		if p == "*" {
+			continue
+		}
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
+
+// Ident is a SQLizer for an identifier, such as a table or column name,
+// quoted with QuoteIdent.
+//
+// SQL returns an error if the identifier, or any of its parts, is empty.
+//
+// Ex:
+//
+//	.OrderByClause(Ident(sortField))
+//	.Where(Eq{QuoteIdent(field): value})
+type Ident string
+
+// SQL returns the quoted identifier.
+func (i Ident) SQL() (sql string, args []any, err error) {
+	if err = validateIdent(string(i)); err != nil {
+		return
+	}
+	sql = QuoteIdent(string(i))
+	return
+}
+
+// validateIdent returns an error if the identifier name, or any of its parts,
+// is empty.
+func validateIdent(name string) error {
+	if name == "" {
+		return errors.New("identifiers cannot be empty")
+	}
+	for _, p := range strings.Split(name, ".") {
+		if p == "" {
+			return fmt.Errorf("identifier %q has an empty part", name)
+		}
+	}
+	return nil
+}
+
+// normalizeIdent returns the name PostgreSQL resolves an identifier to, so that
+// a quoted and an unquoted spelling of the same column compare equal:
+// unquoted parts are folded to lower case and quoted parts are unquoted.
+func normalizeIdent(name string) string {
+	var buf strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '"' {
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			buf.WriteByte(c)
+			continue
+		}
+		for i++; i < len(name); i++ {
+			if name[i] == '"' {
+				if i+1 < len(name) && name[i+1] == '"' {
+					i++
+				} else {
+					break
+				}
+			}
+			buf.WriteByte(name[i])
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,173 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		want string
+	}{
+		{"users", `"users"`},
+		{"Users", `"Users"`},
+		{"public.users.id", `"public"."users"."id"`},
+		{"u.*", `"u".*`},
+		{"*", `*`},
+		{`a"b`, `"a""b"`},
+		{`x"; DROP TABLE users; --`, `"x""; DROP TABLE users; --"`},
+		{"", `""`},
+	}
+	for _, tc := range testCases {
+		if got := QuoteIdent(tc.name); got != tc.want {
+			t.Errorf("expected QuoteIdent(%q) to be %q, got %q instead", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestIdentErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		ident Ident
+		want  string
+	}{
+		{"", "identifiers cannot be empty"},
+		{"public.", `identifier "public." has an empty part`},
+		{"a..b", `identifier "a..b" has an empty part`},
+	}
+	for _, tc := range testCases {
+		_, _, err := tc.ident.SQL()
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+		}
+	}
+}
+
+func TestIdentBuilders(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "select",
+			b: Select().
+				ColumnsIdent("id", `na"me`).
+				Column(Alias{Expr: Ident("u.email"), As: "e"}).
+				FromIdent("public.users").
+				Where(Eq{QuoteIdent("Status"): "active"}).
+				OrderByClause(Ident("created_at")),
+			wantSQL:  `SELECT "id", "na""me", ("u"."email") AS e FROM "public"."users" WHERE "Status" = $1 ORDER BY "created_at"`,
+			wantArgs: []any{"active"},
+		},
+		{
+			name:     "insert",
+			b:        Insert("").IntoIdent("public.users").ColumnsIdent("id", "name").Values(1, "x"),
+			wantSQL:  `INSERT INTO "public"."users" ("id","name") VALUES ($1,$2)`,
+			wantArgs: []any{1, "x"},
+		},
+		{
+			name:     "update",
+			b:        Update("").TableIdent("users").SetIdent("name", "x").Where(Expr("? = ?", Ident("id"), 1)),
+			wantSQL:  `UPDATE "users" SET "name" = $1 WHERE "id" = $2`,
+			wantArgs: []any{"x", 1},
+		},
+		{
+			name:     "delete",
+			b:        Delete("").FromIdent("users").Where(Eq{QuoteIdent("id"): 1}),
+			wantSQL:  `DELETE FROM "users" WHERE "id" = $1`,
+			wantArgs: []any{1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestIdentBuildersErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "select_columns",
+			b:    Select().ColumnsIdent("").From("t"),
+			want: "identifiers cannot be empty",
+		},
+		{
+			name: "select_from",
+			b:    Select("*").FromIdent("public."),
+			want: `identifier "public." has an empty part`,
+		},
+		{
+			name: "insert_into",
+			b:    Insert("").IntoIdent("").Columns("id").Values(1),
+			want: "identifiers cannot be empty",
+		},
+		{
+			name: "insert_columns",
+			b:    Insert("t").ColumnsIdent("id", "t.").Values(1, 2),
+			want: `identifier "t." has an empty part`,
+		},
+		{
+			name: "update_table",
+			b:    Update("").TableIdent(".users").Set("name", "x"),
+			want: `identifier ".users" has an empty part`,
+		},
+		{
+			name: "update_set",
+			b:    Update("users").SetIdent("", "x"),
+			want: "identifiers cannot be empty",
+		},
+		{
+			name: "delete_from",
+			b:    Delete("").FromIdent("public..users"),
+			want: `identifier "public..users" has an empty part`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleQuoteIdent() {
+	sortField := "created_at"
+	sql, args, _ := Select("*").
+		FromIdent("public.users").
+		Where(Eq{QuoteIdent("status"): "active"}).
+		OrderByClause(Ident(sortField)).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT * FROM "public"."users" WHERE "status" = $1 ORDER BY "created_at"
+	// [active]
+}
@@ -12,6 +12,7 @@ import (
 // InsertBuilder builds SQL INSERT statements.
 type InsertBuilder struct {
 	prefixes      []SQLizer
+	ctes          []CTE
 	verb          string
 	into          string
 	columns       []string
@@ -19,6 +20,23 @@ type InsertBuilder struct {
 	returning     []SQLizer
 	suffixes      []SQLizer
 	selectBuilder *SelectBuilder
+	onConflict    onConflict
+	replace       bool
+
+	// err is the first error of a method, such as an invalid identifier,
+	// returned by SQL.
+	err error
+}
+
+// onConflict holds the ON CONFLICT clause of an INSERT statement.
+type onConflict struct {
+	enabled     bool
+	target      []string
+	constraint  string
+	targetWhere []SQLizer
+	doNothing   bool
+	setClauses  []setClause
+	updateWhere []SQLizer
 }
 
 // Verb to be used for the operation (default: INSERT).
@@ -29,6 +47,20 @@ func (b InsertBuilder) Verb(v string) InsertBuilder {
 
 // SQL builds the query into a SQL string and bound args.
 func (b InsertBuilder) SQL() (sqlStr string, args []any, err error) {
+	sqlStr, args, err = b.unfinalizedSQL()
+	if err != nil {
+		return
+	}
+
+	sqlStr, err = dollarPlaceholder(sqlStr)
+	return
+}
+
+func (b InsertBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if b.err != nil {
+		err = b.err
+		return
+	}
 	if b.into == "" {
 		err = errors.New("insert statements must specify a table")
 		return
@@ -49,6 +81,11 @@ func (b InsertBuilder) SQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" ")
 	}
 
+	args, err = appendCTEs(b.ctes, sql, args)
+	if err != nil {
+		return
+	}
+
 	if b.verb != "" {
 		sql.WriteString(b.verb + " ")
 	} else {
@@ -74,6 +111,13 @@ func (b InsertBuilder) SQL() (sqlStr string, args []any, err error) {
 		return
 	}
 
+	if b.onConflict.enabled || b.replace {
+		args, err = b.appendOnConflictToSQL(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
 	if len(b.returning) > 0 {
 		sql.WriteString(" RETURNING ")
 		args, err = appendSQL(b.returning, sql, ", ", args)
@@ -90,7 +134,7 @@ func (b InsertBuilder) SQL() (sqlStr string, args []any, err error) {
 		}
 	}
 
-	sqlStr, err = dollarPlaceholder(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
@@ -106,7 +150,7 @@ func (b InsertBuilder) appendValuesToSQL(w io.Writer, args []any) ([]any, error)
 		valueStrings := make([]string, len(row))
 		for v, val := range row {
 			if vs, ok := val.(SQLizer); ok {
-				vsql, vargs, err := vs.SQL()
+				vsql, vargs, err := nestedSQL(vs)
 				if err != nil {
 					return nil, err
 				}
@@ -130,7 +174,7 @@ func (b InsertBuilder) appendSelectToSQL(w io.Writer, args []any) ([]any, error)
 		return args, errors.New("select clause for insert statements are not set")
 	}
 
-	selectClause, sArgs, err := b.selectBuilder.SQL()
+	selectClause, sArgs, err := b.selectBuilder.unfinalizedSQL()
 	if err != nil {
 		return args, err
 	}
@@ -141,6 +185,107 @@ func (b InsertBuilder) appendSelectToSQL(w io.Writer, args []any) ([]any, error)
 	return args, nil
 }
 
+func (b InsertBuilder) appendOnConflictToSQL(w io.Writer, args []any) ([]any, error) {
+	c := b.onConflict
+	if len(c.target) > 0 && c.constraint != "" {
+		return nil, errors.New("on conflict clause cannot have both a conflict target and a constraint")
+	}
+	if len(c.targetWhere) > 0 && len(c.target) == 0 {
+		return nil, errors.New("on conflict WHERE predicate requires a conflict target")
+	}
+
+	setClauses := c.setClauses
+	if b.replace {
+		if c.doNothing {
+			return nil, errors.New("replace statements cannot use DO NOTHING")
+		}
+		if len(c.target) == 0 && c.constraint == "" {
+			return nil, errors.New("replace statements must specify a conflict target or constraint")
+		}
+		if len(b.columns) == 0 {
+			return nil, errors.New("replace statements must specify the columns")
+		}
+		setClauses = b.replaceSetClauses()
+		if len(setClauses) == 0 {
+			return nil, errors.New("replace statements must have at least one column outside the conflict target")
+		}
+	}
+	if c.doNothing && len(setClauses) > 0 {
+		return nil, errors.New("on conflict clause cannot have both DO NOTHING and DO UPDATE")
+	}
+	if !c.doNothing && len(setClauses) == 0 {
+		return nil, errors.New("on conflict clause must have either DO NOTHING or DO UPDATE")
+	}
+	if len(setClauses) > 0 && len(c.target) == 0 && c.constraint == "" {
+		return nil, errors.New("on conflict DO UPDATE requires a conflict target or constraint")
+	}
+	if len(c.updateWhere) > 0 && len(setClauses) == 0 {
+		return nil, errors.New("on conflict WHERE condition requires DO UPDATE")
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString(" ON CONFLICT")
+	var err error
+	if len(c.target) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(c.target, ", "))
+		sql.WriteString(")")
+		if len(c.targetWhere) > 0 {
+			sql.WriteString(" WHERE ")
+			args, err = appendSQL(c.targetWhere, sql, " AND ", args)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if c.constraint != "" {
+		sql.WriteString(" ON CONSTRAINT ")
+		sql.WriteString(c.constraint)
+	}
+
+	if c.doNothing {
+		sql.WriteString(" DO NOTHING")
+	} else {
+		sql.WriteString(" DO UPDATE SET ")
+		args, err = appendSetClauses(setClauses, sql, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.updateWhere) > 0 {
+			sql.WriteString(" WHERE ")
+			args, err = appendSQL(c.updateWhere, sql, " AND ", args)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, err = io.WriteString(w, sql.String())
+	return args, err
+}
+
+// replaceSetClauses returns the assignments of a full-row upsert: every
+// inserted column outside of the conflict target is set to its EXCLUDED value,
+// unless it is explicitly set with DoUpdateSet. Columns are compared after
+// normalizeIdent, so ColumnsIdent("id") matches OnConflict("id").
+func (b InsertBuilder) replaceSetClauses() []setClause {
+	skip := make(map[string]bool, len(b.onConflict.target)+len(b.onConflict.setClauses))
+	for _, col := range b.onConflict.target {
+		skip[normalizeIdent(col)] = true
+	}
+	for _, sc := range b.onConflict.setClauses {
+		skip[normalizeIdent(sc.column)] = true
+	}
+
+	var setClauses []setClause
+	for _, col := range b.columns {
+		if !skip[normalizeIdent(col)] {
+			setClauses = append(setClauses, setClause{column: col, value: Excluded(col)})
+		}
+	}
+	return append(setClauses, b.onConflict.setClauses...)
+}
+
 // MustSQL builds the query into a SQL string and bound args.
 // It panics if there are any errors.
 func (b InsertBuilder) MustSQL() (string, []any) {
@@ -162,18 +307,62 @@ func (b InsertBuilder) PrefixExpr(expr SQLizer) InsertBuilder {
 	return b
 }
 
+// With adds a common table expression to the WITH clause of the query.
+func (b InsertBuilder) With(name string, expr SQLizer) InsertBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr})
+}
+
+// WithRecursive adds a common table expression to the WITH clause of the query,
+// turning it into WITH RECURSIVE.
+func (b InsertBuilder) WithRecursive(name string, expr SQLizer) InsertBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr, Recursive: true})
+}
+
+// WithCTE adds a common table expression to the WITH clause of the query.
+func (b InsertBuilder) WithCTE(cte CTE) InsertBuilder {
+	b.ctes = append(b.ctes, cte)
+	return b
+}
+
 // Into sets the INTO clause of the query.
 func (b InsertBuilder) Into(from string) InsertBuilder {
 	b.into = from
 	return b
 }
 
+// IntoIdent sets the INTO clause of the query, quoted as an identifier.
+//
+// See QuoteIdent.
+func (b InsertBuilder) IntoIdent(from string) InsertBuilder {
+	b = b.setErr(validateIdent(from))
+	return b.Into(QuoteIdent(from))
+}
+
+// setErr keeps err to be returned by SQL, unless there is an earlier error.
+func (b InsertBuilder) setErr(err error) InsertBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
 // Columns adds insert columns to the query.
 func (b InsertBuilder) Columns(columns ...string) InsertBuilder {
 	b.columns = append(b.columns, columns...)
 	return b
 }
 
+// ColumnsIdent adds insert columns to the query, quoted as identifiers.
+//
+// See QuoteIdent.
+func (b InsertBuilder) ColumnsIdent(columns ...string) InsertBuilder {
+	for _, column := range columns {
+		b = b.setErr(validateIdent(column))
+		b.columns = append(b.columns, QuoteIdent(column))
+	}
+	return b
+}
+
 // Values adds a single row's values to the query.
 func (b InsertBuilder) Values(values ...any) InsertBuilder {
 	b.values = append(b.values, values)
@@ -233,3 +422,76 @@ func (b InsertBuilder) Select(sb SelectBuilder) InsertBuilder {
 	b.selectBuilder = &sb
 	return b
 }
+
+// OnConflict adds an ON CONFLICT clause to the query, with an optional
+// conflict target of column names or index expressions.
+//
+// It must be followed by either DoNothing or DoUpdateSet.
+//
+// Ex:
+//
+//	Insert("users").Columns("email", "name").Values(email, name).
+//		OnConflict("email").
+//		DoUpdateSet("name", Excluded("name"))
+func (b InsertBuilder) OnConflict(target ...string) InsertBuilder {
+	b.onConflict.enabled = true
+	b.onConflict.target = append(b.onConflict.target, target...)
+	return b
+}
+
+// OnConflictOnConstraint adds an ON CONFLICT ON CONSTRAINT clause to the query.
+func (b InsertBuilder) OnConflictOnConstraint(name string) InsertBuilder {
+	b.onConflict.enabled = true
+	b.onConflict.constraint = name
+	return b
+}
+
+// OnConflictWhere adds a predicate to the conflict target, used to infer
+// partial unique indexes.
+//
+// See SelectBuilder.Where for the accepted types.
+func (b InsertBuilder) OnConflictWhere(pred any, args ...any) InsertBuilder {
+	b.onConflict.enabled = true
+	b.onConflict.targetWhere = append(b.onConflict.targetWhere, newWherePart(pred, args...))
+	return b
+}
+
+// DoNothing sets the ON CONFLICT action to DO NOTHING.
+func (b InsertBuilder) DoNothing() InsertBuilder {
+	b.onConflict.enabled = true
+	b.onConflict.doNothing = true
+	return b
+}
+
+// DoUpdateSet adds an assignment to the ON CONFLICT DO UPDATE SET action.
+//
+// Use Excluded to refer to the row proposed for insertion.
+func (b InsertBuilder) DoUpdateSet(column string, value any) InsertBuilder {
+	b.onConflict.enabled = true
+	b.onConflict.setClauses = append(b.onConflict.setClauses, setClause{column: column, value: value})
+	return b
+}
+
+// DoUpdateSetMap is a convenience method which calls .DoUpdateSet for each key/value pair in clauses.
+func (b InsertBuilder) DoUpdateSetMap(clauses map[string]any) InsertBuilder {
+	for _, key := range getSortedKeys(clauses) {
+		b = b.DoUpdateSet(key, clauses[key])
+	}
+	return b
+}
+
+// DoUpdateWhere adds a condition to the ON CONFLICT DO UPDATE action.
+// Rows not satisfying it are not updated.
+//
+// See SelectBuilder.Where for the accepted types.
+func (b InsertBuilder) DoUpdateWhere(pred any, args ...any) InsertBuilder {
+	b.onConflict.enabled = true
+	b.onConflict.updateWhere = append(b.onConflict.updateWhere, newWherePart(pred, args...))
+	return b
+}
+
+// Excluded refers to the value of column in the row proposed for insertion
+// in an ON CONFLICT DO UPDATE action.
+func Excluded(column string) SQLizer {
+	return Expr("EXCLUDED." + column)
+}
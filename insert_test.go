@@ -214,11 +214,11 @@ func TestInsertBuilderSelect(t *testing.T) {
 
 func TestInsertBuilderReplace(t *testing.T) {
 	t.Parallel()
-	b := Replace("table").Values(1)
+	b := Replace("table").Columns("id", "a", "b").Values(1, 2, 3).OnConflict("id")
 
-	want := "REPLACE INTO table VALUES ($1)"
+	want := "INSERT INTO table (id,a,b) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET a = EXCLUDED.a, b = EXCLUDED.b"
 
-	sql, _, err := b.SQL()
+	sql, args, err := b.SQL()
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -226,6 +226,195 @@ func TestInsertBuilderReplace(t *testing.T) {
 	if want != sql {
 		t.Errorf("expected SQL to be %q, got %q instead", want, sql)
 	}
+
+	expectedArgs := []any{1, 2, 3}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Errorf("wanted %v, got %v instead", expectedArgs, args)
+	}
+}
+
+func TestInsertBuilderOnConflict(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        InsertBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "do_nothing",
+			b:        Insert("a").Values(1).OnConflict().DoNothing(),
+			wantSQL:  "INSERT INTO a VALUES ($1) ON CONFLICT DO NOTHING",
+			wantArgs: []any{1},
+		},
+		{
+			name:     "target_do_nothing",
+			b:        Insert("a").Columns("b", "c").Values(1, 2).OnConflict("b", "lower(c)").DoNothing().Returning("id"),
+			wantSQL:  "INSERT INTO a (b,c) VALUES ($1,$2) ON CONFLICT (b, lower(c)) DO NOTHING RETURNING id",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "do_update",
+			b: Insert("a").Columns("b", "c").Values(1, 2).
+				OnConflict("b").
+				DoUpdateSet("c", Excluded("c")).
+				DoUpdateSet("n", Expr("a.n + ?", 1)).
+				DoUpdateSet("d", 3),
+			wantSQL:  "INSERT INTO a (b,c) VALUES ($1,$2) ON CONFLICT (b) DO UPDATE SET c = EXCLUDED.c, n = a.n + $3, d = $4",
+			wantArgs: []any{1, 2, 1, 3},
+		},
+		{
+			name: "do_update_set_map",
+			b: Insert("a").SetMap(map[string]any{"b": 1, "c": 2}).
+				OnConflict("b").
+				DoUpdateSetMap(map[string]any{"d": 4, "c": Excluded("c")}),
+			wantSQL:  "INSERT INTO a (b,c) VALUES ($1,$2) ON CONFLICT (b) DO UPDATE SET c = EXCLUDED.c, d = $3",
+			wantArgs: []any{1, 2, 4},
+		},
+		{
+			name: "on_constraint",
+			b: Insert("a").Columns("b").Values(1).
+				OnConflictOnConstraint("a_b_key").
+				DoUpdateSet("b", Excluded("b")),
+			wantSQL:  "INSERT INTO a (b) VALUES ($1) ON CONFLICT ON CONSTRAINT a_b_key DO UPDATE SET b = EXCLUDED.b",
+			wantArgs: []any{1},
+		},
+		{
+			name: "partial_index_and_update_where",
+			b: Insert("a").Columns("b", "c").Values(1, 2).
+				OnConflict("b").
+				OnConflictWhere("deleted_at IS NULL").
+				OnConflictWhere(Eq{"kind": "x"}).
+				DoUpdateSet("c", Excluded("c")).
+				DoUpdateWhere("a.c <> ?", 5).
+				Returning("id"),
+			wantSQL: "INSERT INTO a (b,c) VALUES ($1,$2) ON CONFLICT (b) WHERE deleted_at IS NULL AND kind = $3 " +
+				"DO UPDATE SET c = EXCLUDED.c WHERE a.c <> $4 RETURNING id",
+			wantArgs: []any{1, 2, "x", 5},
+		},
+		{
+			name: "select",
+			b: Insert("a").Columns("b").Select(Select("b").From("c").Where("d = ?", 1)).
+				OnConflict("b").
+				DoUpdateSet("b", Select("max(b)").From("c").Where("d = ?", 2)),
+			wantSQL:  "INSERT INTO a (b) SELECT b FROM c WHERE d = $1 ON CONFLICT (b) DO UPDATE SET b = (SELECT max(b) FROM c WHERE d = $2)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "replace_on_constraint",
+			b: Replace("a").SetMap(map[string]any{"id": 1, "b": 2, "c": 3}).
+				OnConflictOnConstraint("a_pkey").
+				DoUpdateSet("updated_at", Expr("now()")).
+				DoUpdateSet("c", Expr("a.c + EXCLUDED.c")),
+			wantSQL: "INSERT INTO a (b,c,id) VALUES ($1,$2,$3) ON CONFLICT ON CONSTRAINT a_pkey " +
+				"DO UPDATE SET b = EXCLUDED.b, id = EXCLUDED.id, updated_at = now(), c = a.c + EXCLUDED.c",
+			wantArgs: []any{2, 3, 1},
+		},
+		{
+			name:     "replace_where",
+			b:        Replace("a").Columns("id", "b").Values(1, 2).OnConflict("id").DoUpdateWhere("a.b IS DISTINCT FROM EXCLUDED.b"),
+			wantSQL:  "INSERT INTO a (id,b) VALUES ($1,$2) ON CONFLICT (id) DO UPDATE SET b = EXCLUDED.b WHERE a.b IS DISTINCT FROM EXCLUDED.b",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "replace_columns_ident",
+			b:        Replace("a").ColumnsIdent("id", "Name").Values(1, "x").OnConflict("ID").DoUpdateSet(`"Name"`, "y"),
+			wantSQL:  `INSERT INTO a ("id","Name") VALUES ($1,$2) ON CONFLICT (ID) DO UPDATE SET "Name" = $3`,
+			wantArgs: []any{1, "x", "y"},
+		},
+		{
+			name:     "replace_columns_ident_case",
+			b:        Replace("a").ColumnsIdent("id", "Name").Values(1, "x").OnConflict("id", "name"),
+			wantSQL:  `INSERT INTO a ("id","Name") VALUES ($1,$2) ON CONFLICT (id, name) DO UPDATE SET "Name" = EXCLUDED."Name"`,
+			wantArgs: []any{1, "x"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestInsertBuilderOnConflictErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    InsertBuilder
+		want string
+	}{
+		{
+			name: "no_action",
+			b:    Insert("a").Values(1).OnConflict("b"),
+			want: "on conflict clause must have either DO NOTHING or DO UPDATE",
+		},
+		{
+			name: "both_actions",
+			b:    Insert("a").Values(1).OnConflict("b").DoNothing().DoUpdateSet("b", 1),
+			want: "on conflict clause cannot have both DO NOTHING and DO UPDATE",
+		},
+		{
+			name: "update_without_target",
+			b:    Insert("a").Values(1).OnConflict().DoUpdateSet("b", 1),
+			want: "on conflict DO UPDATE requires a conflict target or constraint",
+		},
+		{
+			name: "target_and_constraint",
+			b:    Insert("a").Values(1).OnConflict("b").OnConflictOnConstraint("c").DoNothing(),
+			want: "on conflict clause cannot have both a conflict target and a constraint",
+		},
+		{
+			name: "target_where_without_target",
+			b:    Insert("a").Values(1).OnConflictWhere("b > 0").DoNothing(),
+			want: "on conflict WHERE predicate requires a conflict target",
+		},
+		{
+			name: "update_where_without_update",
+			b:    Insert("a").Values(1).OnConflict("b").DoNothing().DoUpdateWhere("b > 0"),
+			want: "on conflict WHERE condition requires DO UPDATE",
+		},
+		{
+			name: "replace_without_target",
+			b:    Replace("a").Columns("b").Values(1),
+			want: "replace statements must specify a conflict target or constraint",
+		},
+		{
+			name: "replace_without_columns",
+			b:    Replace("a").Values(1).OnConflict("b"),
+			want: "replace statements must specify the columns",
+		},
+		{
+			name: "replace_only_target_columns",
+			b:    Replace("a").Columns("b").Values(1).OnConflict("b"),
+			want: "replace statements must have at least one column outside the conflict target",
+		},
+		{
+			name: "replace_do_nothing",
+			b:    Replace("a").Columns("b").Values(1).OnConflict("b").DoNothing(),
+			want: "replace statements cannot use DO NOTHING",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
 }
 
 func TestInsertBuilderVerb(t *testing.T) {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/henvic/pgq"
+	"github.com/henvic/pgq/jsonb"
 	"github.com/henvic/pgtools/sqltest"
 	"github.com/jackc/pgx/v5"
 )
@@ -560,6 +561,269 @@ func TestValidQueries(t *testing.T) {
 			pgq.Update("test").SetMap(pgq.Eq{"x": 1, "y": 2}),
 			"UPDATE test SET x = $1, y = $2",
 		},
+		{
+			"insert_on_conflict_do_update",
+			pgq.Insert("atable").Columns("id", "name").Values(1, "a").
+				OnConflict("id").
+				OnConflictWhere("deleted_at IS NULL").
+				DoUpdateSet("name", pgq.Excluded("name")).
+				DoUpdateWhere("atable.name <> ?", "b"),
+			"INSERT INTO atable (id,name) VALUES ($1,$2) ON CONFLICT (id) WHERE deleted_at IS NULL " +
+				"DO UPDATE SET name = EXCLUDED.name WHERE atable.name <> $3",
+		},
+		{
+			"replace",
+			pgq.Replace("atable").Columns("id", "name").Values(1, "a").OnConflictOnConstraint("atable_pkey"),
+			"INSERT INTO atable (id,name) VALUES ($1,$2) ON CONFLICT ON CONSTRAINT atable_pkey DO UPDATE SET id = EXCLUDED.id, name = EXCLUDED.name",
+		},
+		{
+			"merge",
+			pgq.Merge("customer_account ca").
+				UsingSelect(pgq.Select("customer_id", "transaction_value").From("recent_transactions").Where("day = ?", "2025-04-10"), "t").
+				On("t.customer_id = ca.customer_id").
+				WhenMatchedAnd(pgq.Expr("t.transaction_value > ?", 0), pgq.MergeUpdate().Set("balance", pgq.Expr("balance + t.transaction_value"))).
+				WhenMatched(pgq.MergeDelete()).
+				WhenNotMatched(pgq.MergeInsert("customer_id", "balance").Values(pgq.Expr("t.customer_id"), pgq.Expr("t.transaction_value"))),
+			"MERGE INTO customer_account ca " +
+				"USING (SELECT customer_id, transaction_value FROM recent_transactions WHERE day = $1) AS t " +
+				"ON t.customer_id = ca.customer_id " +
+				"WHEN MATCHED AND t.transaction_value > $2 THEN UPDATE SET balance = balance + t.transaction_value " +
+				"WHEN MATCHED THEN DELETE " +
+				"WHEN NOT MATCHED THEN INSERT (customer_id, balance) VALUES (t.customer_id, t.transaction_value)",
+		},
+		{
+			"with_delete_insert_select",
+			pgq.With("moved", pgq.Delete("products").Where("sold = ?", true).Returning("*")).
+				Insert("archive").
+				Select(pgq.Select("*").From("moved")),
+			"WITH moved AS (DELETE FROM products WHERE sold = $1 RETURNING *) INSERT INTO archive SELECT * FROM moved",
+		},
+		{
+			"with_recursive_materialized",
+			pgq.Select("n").
+				WithCTE(pgq.CTE{
+					Name:         "t",
+					Columns:      []string{"n"},
+					Recursive:    true,
+					Materialized: true,
+					Expr:         pgq.Expr("VALUES (1) UNION ALL ?", pgq.Select("n+1").From("t").Where("n < ?", 100)),
+				}).
+				From("t"),
+			"WITH RECURSIVE t(n) AS MATERIALIZED (VALUES (1) UNION ALL SELECT n+1 FROM t WHERE n < $1) SELECT n FROM t",
+		},
+		{
+			"union_intersect_order_limit",
+			pgq.Select("id").From("a").Where("x = ?", 1).
+				Union(pgq.Select("id").From("b").OrderBy("id").Limit(5)).
+				Intersect(pgq.Select("id").From("c")).
+				OrderBy("id DESC").
+				Limit(10),
+			"(SELECT id FROM a WHERE x = $1 UNION (SELECT id FROM b ORDER BY id LIMIT 5)) " +
+				"INTERSECT SELECT id FROM c ORDER BY id DESC LIMIT 10",
+		},
+		{
+			"for_update_skip_locked",
+			pgq.Select("*").From("jobs j").Join("workers w ON w.id = j.worker_id").
+				Where("j.status = ?", "pending").
+				OrderBy("j.id").
+				Limit(10).
+				ForUpdate("j").SkipLocked().
+				ForShare("w").NoWait(),
+			"SELECT * FROM jobs j JOIN workers w ON w.id = j.worker_id WHERE j.status = $1 " +
+				"ORDER BY j.id LIMIT 10 FOR UPDATE OF j SKIP LOCKED FOR SHARE OF w NOWAIT",
+		},
+		{
+			"distinct_on",
+			pgq.Select("*").
+				DistinctOn("kind", "date_trunc('day', ts)").
+				From("events").
+				OrderBy("kind", "date_trunc('day', ts)", "ts DESC"),
+			"SELECT DISTINCT ON (kind, date_trunc('day', ts)) * FROM events ORDER BY kind, date_trunc('day', ts), ts DESC",
+		},
+		{
+			"quoted_identifiers",
+			pgq.Select().
+				ColumnsIdent("u.id", `we"ird`).
+				FromIdent("public.users").
+				Where(pgq.Eq{pgq.QuoteIdent("u.Status"): "active"}).
+				OrderByClause(pgq.Ident("created_at")),
+			`SELECT "u"."id", "we""ird" FROM "public"."users" WHERE "u"."Status" = $1 ORDER BY "created_at"`,
+		},
+		{
+			"window_functions",
+			pgq.Select("id").
+				Column(pgq.Over("sum(id)", pgq.Window().OrderBy("id").RowsBetween(pgq.Preceding(1), pgq.CurrentRow()))).
+				Column(pgq.OverWindow("rank()", "w")).
+				From("users").
+				Window("w", pgq.Window().PartitionBy("status").OrderBy("created_at DESC")),
+			"SELECT id, sum(id) OVER (ORDER BY id ROWS BETWEEN $1 PRECEDING AND CURRENT ROW), rank() OVER w FROM users WINDOW w AS (PARTITION BY status ORDER BY created_at DESC)",
+		},
+		{
+			"aggregates",
+			pgq.Select("status").
+				Column(pgq.Agg("array_agg", "id").Distinct().OrderBy("id DESC").Filter(pgq.Gt{"id": 0})).
+				From("users").
+				GroupBy("status").
+				Having(pgq.Expr("? > ?", pgq.Agg("count", "*"), 1)),
+			"SELECT status, array_agg(DISTINCT id ORDER BY id DESC) FILTER (WHERE id > $1) FROM users GROUP BY status HAVING count(*) > $2",
+		},
+		{
+			"grouping_sets",
+			pgq.Select("status", "created_at::date").
+				Column(pgq.Grouping("status", "created_at::date")).
+				Column(pgq.Agg("count", "*")).
+				From("users").
+				GroupByGroupingSets(pgq.GroupingSet("status", "created_at::date"), "status", pgq.GroupingSet()),
+			"SELECT status, created_at::date, GROUPING(status, created_at::date), count(*) FROM users GROUP BY GROUPING SETS ((status, created_at::date), status, ())",
+		},
+		{
+			"sort",
+			pgq.Select("id").
+				From("users").
+				Sort(pgq.Desc("created_at").NullsLast(), pgq.Asc(pgq.Ident("id")).Using("<")),
+			`SELECT id FROM users ORDER BY created_at DESC NULLS LAST, "id" USING <`,
+		},
+		{
+			"keyset",
+			pgq.Select("id").
+				From("users").
+				Keyset(pgq.Keyset(pgq.Desc("created_at"), pgq.Asc("id")).Limit(10).After("2024-05-01T10:00:00Z", 42)),
+			"SELECT id FROM users WHERE (created_at < $1 OR (created_at = $2 AND id > $3)) ORDER BY created_at DESC, id ASC LIMIT 11",
+		},
+		{
+			"fetch_first_with_ties",
+			pgq.Select("id").From("users").OrderBy("status").OffsetExpr(1).FetchNext(2).WithTies(),
+			"SELECT id FROM users ORDER BY status OFFSET $1 FETCH NEXT $2 ROWS WITH TIES",
+		},
+		{
+			"limit_expr",
+			pgq.Select("id").From("users").LimitExpr(10).OffsetExpr(20),
+			"SELECT id FROM users LIMIT $1 OFFSET $2",
+		},
+		{
+			"join_builder",
+			pgq.Select("u.name", "o.total").
+				From("users u").
+				JoinClause(pgq.JoinTable("teams t").Inner().Using("team_id")).
+				JoinClause(pgq.JoinSelect(pgq.Select("total").From("orders").Where("user_id = u.id AND status = ?", "paid").Limit(1), "o").
+					Left().Lateral().On("true")).
+				JoinClause(pgq.JoinTable("profiles p").FullOuter().On("p.user_id = u.id").On(pgq.Eq{"p.kind": "main"})).
+				Where("u.active = ?", true),
+			"SELECT u.name, o.total FROM users u INNER JOIN teams t USING (team_id) " +
+				"LEFT JOIN LATERAL (SELECT total FROM orders WHERE user_id = u.id AND status = $1 LIMIT 1) AS o ON true " +
+				"FULL OUTER JOIN profiles p ON p.user_id = u.id AND p.kind = $2 WHERE u.active = $3",
+		},
+		{
+			"subquery_predicates",
+			pgq.Select("k").
+				From("pgq_integration i").
+				Where(pgq.Exists(pgq.Select("1").From("pgq_integration j").Where("j.k > i.k AND j.v = ?", "foo"))).
+				Where(pgq.NotEq{"k": pgq.Select("k").From("pgq_integration").Where("v = ?", "baz")}).
+				Where(pgq.Any("k", "<", pgq.Select("k").From("pgq_integration"))).
+				Where(pgq.All("k", "<>", []int{7, 8})),
+			"SELECT k FROM pgq_integration i WHERE EXISTS (SELECT 1 FROM pgq_integration j WHERE j.k > i.k AND j.v = $1) " +
+				"AND k NOT IN (SELECT k FROM pgq_integration WHERE v = $2) AND k < ANY (SELECT k FROM pgq_integration) AND k <> ALL ($3)",
+		},
+		{
+			"comparison_predicates",
+			pgq.Select("k").
+				From("pgq_integration").
+				Where(pgq.Between{"k": []int{1, 10}}).
+				Where(pgq.NotBetweenSymmetric{"k": []int{5, 3}}).
+				Where(pgq.IsNotDistinctFrom{"v": "foo"}).
+				Where(pgq.IsNot{"k > 2": false}).
+				Where(pgq.Not(pgq.Eq{"v": "bar"})).
+				Where(pgq.RowComparison{Columns: []string{"k", "v"}, Op: ">", Values: []any{1, "a"}}),
+			"SELECT k FROM pgq_integration WHERE k BETWEEN $1 AND $2 AND k NOT BETWEEN SYMMETRIC $3 AND $4 " +
+				"AND v IS NOT DISTINCT FROM $5 AND k > 2 IS NOT FALSE AND NOT (v = $6) AND (k, v) > ($7, $8)",
+		},
+		{
+			"jsonb",
+			pgq.Select("k").
+				Column(jsonb.GetText(jsonb.Get("v::jsonb", "a"), 0)).
+				Column(jsonb.BuildObject(map[string]any{"k": pgq.Expr("k"), "x": 1})).
+				From("pgq_integration").
+				Where(jsonb.Contains("v::jsonb", map[string]any{"a": true})).
+				Where(jsonb.HasKey("v::jsonb", "a")).
+				Where(jsonb.HasAllKeys("v::jsonb", "a", "b")).
+				Where(jsonb.PathExists("v::jsonb", "$.a ? (@ > 1)")).
+				Where(jsonb.PathMatch(jsonb.Set("v::jsonb", []string{"a"}, 2), "$.a == 2")),
+			"SELECT k, v::jsonb -> $1::text ->> $2::int, jsonb_build_object($3::text, k, $4::text, $5::jsonb) FROM pgq_integration " +
+				"WHERE v::jsonb @> $6::jsonb AND v::jsonb ? $7 AND v::jsonb ?& $8::text[] AND v::jsonb @? $9::jsonpath " +
+				"AND jsonb_set(v::jsonb, $10::text[], $11::jsonb) @@ $12::jsonpath",
+		},
+		{
+			"arrays",
+			pgq.Select("k", "u.x").
+				From("pgq_integration").
+				JoinClause(pgq.JoinTable(pgq.Expr("? AS u(x)", pgq.Unnest([]int{1, 2}))).Cross()).
+				Where(pgq.ArrayContains{"ARRAY[k]": []int{1}}).
+				Where(pgq.ArrayOverlap{"ARRAY[v]": pgq.Array([]string{"foo", "bar"})}).
+				Where(pgq.Expr("? > ?", pgq.Cardinality(pgq.Array([]int{1})), 0)).
+				Where(pgq.Expr("? IS NOT NULL", pgq.ArrayLength("ARRAY[k]", 1))).
+				Where(pgq.Like{"v": []string{"f%", "b%"}}),
+			"SELECT k, u.x FROM pgq_integration CROSS JOIN unnest($1) AS u(x) WHERE ARRAY[k] @> $2 AND ARRAY[v] && ARRAY[$3, $4] " +
+				"AND cardinality(ARRAY[$5]) > $6 AND array_length(ARRAY[k], $7) IS NOT NULL AND v LIKE ANY ($8)",
+		},
+		{
+			"text_search",
+			pgq.Select("k").
+				Column(pgq.Alias{Expr: pgq.TSHeadline("english", "v", "q", "MaxWords=20"), As: "excerpt"}).
+				Column(pgq.Alias{Expr: pgq.TSRankCD(pgq.ToTSVector("english", "v"), "q"), As: "rank"}).
+				From("pgq_integration").
+				JoinClause(pgq.JoinTable(pgq.Expr("? AS q", pgq.WebsearchToTSQuery("english", `"foo bar" -baz`))).Cross()).
+				Where(pgq.Match(pgq.ToTSVector("", "v"), "q")).
+				Where(pgq.Match(pgq.ToTSVector("simple", "v"), pgq.PhraseToTSQuery("simple", "foo bar"))).
+				Where(pgq.Expr("? > 0", pgq.TSRank(pgq.ToTSVector("english", "v"), pgq.PlainToTSQuery("english", "foo")))),
+			"SELECT k, (ts_headline($1::regconfig, v, q, $2)) AS excerpt, (ts_rank_cd(to_tsvector($3::regconfig, v), q)) AS rank " +
+				"FROM pgq_integration CROSS JOIN websearch_to_tsquery($4::regconfig, $5) AS q " +
+				"WHERE to_tsvector(v) @@ q AND to_tsvector($6::regconfig, v) @@ phraseto_tsquery($7::regconfig, $8) " +
+				"AND ts_rank(to_tsvector($9::regconfig, v), plainto_tsquery($10::regconfig, $11)) > 0",
+		},
+		{
+			"ranges",
+			pgq.Select("k").
+				From("pgq_integration").
+				Where(pgq.RangeContains{"int4range(k, k + 10)": pgq.Expr("?::int", 5)}).
+				Where(pgq.RangeContainedBy{"int4range(k, k + 1)": pgq.Int4Range(0, 100, "[]")}).
+				Where(pgq.RangeOverlaps{"int8range(k, NULL)": pgq.Int8Range(1, nil, "")}).
+				Where(pgq.RangeAdjacent{"numrange(k, k + 1)": pgq.NumRange(0, 1, "()")}).
+				Where(pgq.RangeLeftOf{"daterange(now()::date, NULL)": pgq.DateRange(nil, "2024-01-01", "")}).
+				Where(pgq.RangeRightOf{"tstzmultirange(tstzrange(now(), NULL))": pgq.Multirange("tstzmultirange", pgq.TstzRange(nil, "2024-01-01", "(]"))}),
+			"SELECT k FROM pgq_integration WHERE int4range(k, k + 10) @> $1::int AND int4range(k, k + 1) <@ int4range($2, $3, $4) " +
+				"AND int8range(k, NULL) && int8range($5, $6) AND numrange(k, k + 1) -|- numrange($7, $8, $9) " +
+				"AND daterange(now()::date, NULL) << daterange($10, $11) " +
+				"AND tstzmultirange(tstzrange(now(), NULL)) >> tstzmultirange(tstzrange($12, $13, $14))",
+		},
+		{
+			"functions",
+			pgq.Select("k").
+				Column(pgq.Alias{Expr: pgq.Coalesce(pgq.NullIf(pgq.Expr("v"), ""), "none"), As: "label"}).
+				Column(pgq.Alias{Expr: pgq.Func("date_trunc", "day", pgq.Now()), As: "day"}).
+				From("pgq_integration").
+				Where(pgq.Any("k", "=", pgq.Typed([]int{1, 2}, "int[]"))).
+				Where(pgq.Expr("? < ?", pgq.Least(pgq.Expr("k"), 10), pgq.Greatest(pgq.Cast("5", "int"), 1))),
+			"SELECT k, (coalesce(nullif(v, $1), $2)) AS label, (date_trunc($3, now())) AS day FROM pgq_integration " +
+				"WHERE k = ANY ($4::int[]) AND least(k, $5) < greatest(CAST($6 AS int), $7)",
+		},
+		{
+			"col",
+			pgq.Select("i.k").
+				Column(pgq.Col("i.k").Add(1).Mul(2)).
+				From("pgq_integration i").
+				Join("pgq_integration j ON j.k = i.k").
+				Where(pgq.Col("i.k").EqCol("j.k")).
+				Where(pgq.Or{pgq.Col("i.v").ILike("f%"), pgq.Col("i.v").IsNull()}).
+				Where(pgq.Col("i.k").Between(1, pgq.Col("j.k").Add(10))).
+				Where(pgq.Col("i.k").NotIn([]int{5, 6})).
+				Where(pgq.Col("i.k").In(pgq.Select("k").From("pgq_integration"))).
+				GroupBy("i.k").
+				Having(pgq.Col("count(*)").Gt(0)).
+				Sort(pgq.Col("i.k").Desc()),
+			"SELECT i.k, (i.k + $1) * $2 FROM pgq_integration i JOIN pgq_integration j ON j.k = i.k " +
+				"WHERE i.k = j.k AND (i.v ILIKE $3 OR i.v IS NULL) AND i.k BETWEEN $4 AND (j.k + $5) AND i.k <> ALL ($6) " +
+				"AND i.k IN (SELECT k FROM pgq_integration) GROUP BY i.k HAVING count(*) > $7 ORDER BY i.k DESC",
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
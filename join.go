@@ -0,0 +1,184 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	joinDefault   = "JOIN"
+	joinInner     = "INNER JOIN"
+	joinLeft      = "LEFT JOIN"
+	joinRight     = "RIGHT JOIN"
+	joinFullOuter = "FULL OUTER JOIN"
+	joinCross     = "CROSS JOIN"
+)
+
+// JoinBuilder builds a join for SelectBuilder.JoinClause, to a table,
+// function, or subquery, with an ON condition or USING columns.
+//
+// Ex:
+//
+//	Select("u.name", "o.total").From("users u").
+//		JoinClause(JoinTable("orders o").Left().On("o.user_id = u.id").On(Eq{"o.status": "paid"}))
+//	// SELECT u.name, o.total FROM users u LEFT JOIN orders o ON o.user_id = u.id AND o.status = $1
+type JoinBuilder struct {
+	kind    string
+	natural bool
+	lateral bool
+	source  SQLizer
+	sub     bool
+	alias   string
+	on      []SQLizer
+	using   []string
+}
+
+// JoinTable returns a new JoinBuilder joining table, which can be a string,
+// such as a table name with an optional alias, or a SQLizer, such as Expr for
+// a function call.
+func JoinTable(table any) JoinBuilder {
+	return JoinBuilder{kind: joinDefault, source: newPart(table)}
+}
+
+// JoinSelect returns a new JoinBuilder joining the subquery with an alias.
+func JoinSelect(sub SelectBuilder, alias string) JoinBuilder {
+	return JoinBuilder{kind: joinDefault, source: sub, sub: true, alias: alias}
+}
+
+// SQL returns the join clause.
+func (j JoinBuilder) SQL() (string, []any, error) {
+	return resolvedSQL(j)
+}
+
+func (j JoinBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if err = j.validate(); err != nil {
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	if j.natural {
+		sql.WriteString("NATURAL ")
+	}
+	sql.WriteString(j.kind)
+	sql.WriteString(" ")
+	if j.lateral {
+		sql.WriteString("LATERAL ")
+	}
+
+	source, args, err := nestedSQL(j.source)
+	if err != nil {
+		return
+	}
+	if source == "" {
+		err = errors.New("joins must have a table")
+		return
+	}
+	if j.sub {
+		source = "(" + source + ") AS " + j.alias
+	}
+	sql.WriteString(source)
+
+	if len(j.on) > 0 {
+		sql.WriteString(" ON ")
+		args, err = appendSQL(j.on, sql, " AND ", args)
+		if err != nil {
+			return
+		}
+	}
+	if len(j.using) > 0 {
+		sql.WriteString(" USING (")
+		sql.WriteString(strings.Join(j.using, ", "))
+		sql.WriteString(")")
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+func (j JoinBuilder) validate() error {
+	if j.source == nil {
+		return errors.New("joins must be created with JoinTable or JoinSelect")
+	}
+	if j.sub && j.alias == "" {
+		return errors.New("subquery joins must have an alias")
+	}
+	hasCondition := len(j.on) > 0 || len(j.using) > 0
+	switch {
+	case len(j.on) > 0 && len(j.using) > 0:
+		return fmt.Errorf("%s cannot have both ON and USING", j.kind)
+	case j.natural && j.kind == joinCross:
+		return errors.New("NATURAL cannot be used with CROSS JOIN")
+	case j.natural && hasCondition:
+		return fmt.Errorf("NATURAL %s cannot have ON or USING", j.kind)
+	case j.kind == joinCross && hasCondition:
+		return fmt.Errorf("%s cannot have ON or USING", j.kind)
+	case !j.natural && j.kind != joinCross && !hasCondition:
+		return fmt.Errorf("%s must have ON or USING", j.kind)
+	}
+	return nil
+}
+
+// Inner makes the join an INNER JOIN.
+func (j JoinBuilder) Inner() JoinBuilder {
+	j.kind = joinInner
+	return j
+}
+
+// Left makes the join a LEFT JOIN.
+func (j JoinBuilder) Left() JoinBuilder {
+	j.kind = joinLeft
+	return j
+}
+
+// Right makes the join a RIGHT JOIN.
+func (j JoinBuilder) Right() JoinBuilder {
+	j.kind = joinRight
+	return j
+}
+
+// FullOuter makes the join a FULL OUTER JOIN.
+func (j JoinBuilder) FullOuter() JoinBuilder {
+	j.kind = joinFullOuter
+	return j
+}
+
+// Cross makes the join a CROSS JOIN, which has no ON or USING.
+func (j JoinBuilder) Cross() JoinBuilder {
+	j.kind = joinCross
+	return j
+}
+
+// Natural makes the join a NATURAL join, on all the columns with the same
+// names in both sides, which has no ON or USING.
+func (j JoinBuilder) Natural() JoinBuilder {
+	j.natural = true
+	return j
+}
+
+// Lateral makes the join LATERAL, so the subquery or function can reference
+// columns of the preceding FROM items.
+func (j JoinBuilder) Lateral() JoinBuilder {
+	j.lateral = true
+	return j
+}
+
+// On adds a condition to the ON clause of the join.
+// Multiple calls are joined with AND.
+//
+// See SelectBuilder.Where.
+func (j JoinBuilder) On(pred any, args ...any) JoinBuilder {
+	if pred == nil || pred == "" {
+		return j
+	}
+	j.on = append(j.on, newWherePart(pred, args...))
+	return j
+}
+
+// Using adds columns to the USING clause of the join, which must exist with
+// the same names in both sides.
+func (j JoinBuilder) Using(columns ...string) JoinBuilder {
+	j.using = append(j.using, columns...)
+	return j
+}
@@ -0,0 +1,205 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestJoinBuilder(t *testing.T) {
+	t.Parallel()
+	users := Select("u.name").From("users u").Where("u.active = ?", true)
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "join_on",
+			b:       JoinTable("orders o").On("o.user_id = u.id"),
+			wantSQL: "JOIN orders o ON o.user_id = u.id",
+		},
+		{
+			name:     "left_join_on_many",
+			b:        JoinTable("orders o").Left().On("o.user_id = u.id").On(Eq{"o.status": "paid"}).On(map[string]any{"o.deleted": false}),
+			wantSQL:  "LEFT JOIN orders o ON o.user_id = u.id AND o.status = ? AND o.deleted = ?",
+			wantArgs: []any{"paid", false},
+		},
+		{
+			name:     "inner_join_on_and",
+			b:        JoinTable(Ident("Orders")).Inner().On(And{Expr("o.total > ?", 10), Lt{"o.total": 100}}),
+			wantSQL:  `INNER JOIN "Orders" ON (o.total > ? AND o.total < ?)`,
+			wantArgs: []any{10, 100},
+		},
+		{
+			name:    "right_join_using",
+			b:       JoinTable("profiles").Right().Using("user_id", "tenant_id"),
+			wantSQL: "RIGHT JOIN profiles USING (user_id, tenant_id)",
+		},
+		{
+			name:    "full_outer_join",
+			b:       JoinTable("b").FullOuter().On("a.id = b.id"),
+			wantSQL: "FULL OUTER JOIN b ON a.id = b.id",
+		},
+		{
+			name:    "natural_join",
+			b:       JoinTable("profiles").Natural().Left(),
+			wantSQL: "NATURAL LEFT JOIN profiles",
+		},
+		{
+			name:     "cross_join_lateral_function",
+			b:        JoinTable(Expr("generate_series(1, ?) AS g(n)", 3)).Cross().Lateral(),
+			wantSQL:  "CROSS JOIN LATERAL generate_series(1, ?) AS g(n)",
+			wantArgs: []any{3},
+		},
+		{
+			name: "subquery",
+			b: users.JoinClause(
+				JoinSelect(Select("user_id", "sum(total) AS total").From("orders").Where("status = ?", "paid").GroupBy("user_id"), "o").
+					On("o.user_id = u.id").
+					On("o.total > ?", 100),
+			).Where("u.country = ?", "NL"),
+			wantSQL: "SELECT u.name FROM users u " +
+				"JOIN (SELECT user_id, sum(total) AS total FROM orders WHERE status = $1 GROUP BY user_id) AS o ON o.user_id = u.id AND o.total > $2 " +
+				"WHERE u.active = $3 AND u.country = $4",
+			wantArgs: []any{"paid", 100, true, "NL"},
+		},
+		{
+			name: "lateral_subquery",
+			b: Select("u.name", "o.total").
+				Prefix("/* ? */ WITH x AS (SELECT ?)", 0).
+				From("users u").
+				Join("teams t ON t.id = u.team_id AND t.kind = ?", "dev").
+				JoinClause(
+					JoinSelect(Select("total").From("orders").Where("user_id = u.id AND total > ?", 10).OrderBy("total DESC").Limit(1), "o").
+						Left().
+						Lateral().
+						On("true"),
+				).
+				Where("u.active = ?", true),
+			wantSQL: "/* ? */ WITH x AS (SELECT $1) SELECT u.name, o.total FROM users u " +
+				"JOIN teams t ON t.id = u.team_id AND t.kind = $2 " +
+				"LEFT JOIN LATERAL (SELECT total FROM orders WHERE user_id = u.id AND total > $3 ORDER BY total DESC LIMIT 1) AS o ON true " +
+				"WHERE u.active = $4",
+			wantArgs: []any{0, "dev", 10, true},
+		},
+		{
+			name:    "full_join",
+			b:       Select("*").From("a").FullJoin("b USING (id)"),
+			wantSQL: "SELECT * FROM a FULL OUTER JOIN b USING (id)",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestJoinBuilderErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "zero",
+			b:    JoinBuilder{},
+			want: "joins must be created with JoinTable or JoinSelect",
+		},
+		{
+			name: "no_table",
+			b:    JoinTable("").On("true"),
+			want: "joins must have a table",
+		},
+		{
+			name: "no_alias",
+			b:    JoinSelect(Select("1"), "").On("true"),
+			want: "subquery joins must have an alias",
+		},
+		{
+			name: "no_condition",
+			b:    JoinTable("t").Left(),
+			want: "LEFT JOIN must have ON or USING",
+		},
+		{
+			name: "on_and_using",
+			b:    JoinTable("t").On("a = b").Using("id"),
+			want: "JOIN cannot have both ON and USING",
+		},
+		{
+			name: "cross_on",
+			b:    JoinTable("t").Cross().On("a = b"),
+			want: "CROSS JOIN cannot have ON or USING",
+		},
+		{
+			name: "natural_using",
+			b:    JoinTable("t").Natural().Inner().Using("id"),
+			want: "NATURAL INNER JOIN cannot have ON or USING",
+		},
+		{
+			name: "natural_cross",
+			b:    JoinTable("t").Natural().Cross(),
+			want: "NATURAL cannot be used with CROSS JOIN",
+		},
+		{
+			name: "subquery",
+			b:    JoinSelect(Select(), "s").On("true"),
+			want: "select statements must have at least one result column",
+		},
+		{
+			name: "on",
+			b:    JoinTable("t").On(1),
+			want: "expected string-keyed map or string, not int",
+		},
+		{
+			name: "select",
+			b:    Select("*").From("a").JoinClause(JoinTable("b")),
+			want: "JOIN must have ON or USING",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleJoinSelect() {
+	latest := Select("created_at").
+		From("orders").
+		Where("user_id = u.id AND status = ?", "paid").
+		OrderBy("created_at DESC").
+		Limit(1)
+
+	sql, args, _ := Select("u.name", "o.created_at").
+		From("users u").
+		JoinClause(JoinSelect(latest, "o").Left().Lateral().On("true")).
+		Where(Eq{"u.country": "NL"}).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT u.name, o.created_at FROM users u LEFT JOIN LATERAL (SELECT created_at FROM orders WHERE user_id = u.id AND status = $1 ORDER BY created_at DESC LIMIT 1) AS o ON true WHERE u.country = $2
+	// [paid NL]
+}
@@ -0,0 +1,248 @@
+// Package jsonb provides SQLizers for the PostgreSQL jsonb operators and
+// functions, to use with pgq in Where, Column, UpdateBuilder.Set, and anywhere
+// else a pgq.SQLizer is accepted.
+//
+// Documents are a column or expression string, such as "data", or a
+// pgq.SQLizer, including the expressions of this package, so they can be
+// chained:
+//
+//	jsonb.GetText(jsonb.Get("data", "address"), "city")
+//	// data -> ?::text ->> ?::text
+//
+// Values that aren't a pgq.SQLizer are encoded with encoding/json and bound
+// as jsonb arguments. Use json.RawMessage to pass JSON that is already encoded.
+//
+// The ?, ?|, ?&, and @? operators are written with the escaped "??", so they
+// are never mistaken for placeholders.
+//
+// See https://www.postgresql.org/docs/current/functions-json.html
+package jsonb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/henvic/pgq"
+)
+
+// Get returns the field or array element of doc with the -> operator.
+// key is a string for an object field, or an integer for an array element,
+// which can be negative to count from the end.
+//
+// Ex:
+//
+//	Get("data", "address") == "data -> ?::text"
+func Get(doc, key any) pgq.SQLizer {
+	return pgq.Expr("? -> ?", operand(doc), keyValue(key))
+}
+
+// GetText returns the field or array element of doc as text with the ->>
+// operator.
+//
+// Ex:
+//
+//	GetText("data", "name") == "data ->> ?::text"
+func GetText(doc, key any) pgq.SQLizer {
+	return pgq.Expr("? ->> ?", operand(doc), keyValue(key))
+}
+
+// Path returns the element of doc at path with the #> operator.
+//
+// Ex:
+//
+//	Path("data", "address", "city") == "data #> ?::text[]"
+func Path(doc any, path ...string) pgq.SQLizer {
+	return pgq.Expr("? #> ?::text[]", operand(doc), path)
+}
+
+// PathText returns the element of doc at path as text with the #>> operator.
+//
+// Ex:
+//
+//	PathText("data", "address", "city") == "data #>> ?::text[]"
+func PathText(doc any, path ...string) pgq.SQLizer {
+	return pgq.Expr("? #>> ?::text[]", operand(doc), path)
+}
+
+// Contains checks if doc contains value with the @> operator.
+//
+// Ex:
+//
+//	Contains("data", map[string]any{"active": true}) == "data @> ?::jsonb"
+func Contains(doc, value any) pgq.SQLizer {
+	return pgq.Expr("? @> ?", operand(doc), jsonValue(value))
+}
+
+// ContainedBy checks if doc is contained in value with the <@ operator.
+//
+// Ex:
+//
+//	ContainedBy("data", map[string]any{"a": 1, "b": 2}) == "data <@ ?::jsonb"
+func ContainedBy(doc, value any) pgq.SQLizer {
+	return pgq.Expr("? <@ ?", operand(doc), jsonValue(value))
+}
+
+// HasKey checks if key exists as a top-level key or array element of doc with
+// the ? operator.
+//
+// Ex:
+//
+//	HasKey("data", "email") == "data ?? ?"
+func HasKey(doc any, key string) pgq.SQLizer {
+	return pgq.Expr("? ?? ?", operand(doc), key)
+}
+
+// HasAnyKey checks if any of the keys exist in doc with the ?| operator.
+//
+// Ex:
+//
+//	HasAnyKey("data", "email", "phone") == "data ??| ?::text[]"
+func HasAnyKey(doc any, keys ...string) pgq.SQLizer {
+	return pgq.Expr("? ??| ?::text[]", operand(doc), keys)
+}
+
+// HasAllKeys checks if all the keys exist in doc with the ?& operator.
+//
+// Ex:
+//
+//	HasAllKeys("data", "email", "phone") == "data ??& ?::text[]"
+func HasAllKeys(doc any, keys ...string) pgq.SQLizer {
+	return pgq.Expr("? ??& ?::text[]", operand(doc), keys)
+}
+
+// PathExists checks if the jsonpath returns any item for doc with the @?
+// operator.
+//
+// Ex:
+//
+//	PathExists("data", "$.tags[*] ? (@ == \"go\")") == "data @?? ?::jsonpath"
+func PathExists(doc any, path string) pgq.SQLizer {
+	return pgq.Expr("? @?? ?::jsonpath", operand(doc), path)
+}
+
+// PathMatch returns the result of the jsonpath predicate check for doc with
+// the @@ operator.
+//
+// Ex:
+//
+//	PathMatch("data", "$.age >= 18") == "data @@ ?::jsonpath"
+func PathMatch(doc any, path string) pgq.SQLizer {
+	return pgq.Expr("? @@ ?::jsonpath", operand(doc), path)
+}
+
+// Set returns doc with the element at path replaced by value, or added if it
+// doesn't exist, with the jsonb_set function.
+//
+// Ex:
+//
+//	Set("data", []string{"address", "city"}, "Amsterdam") == "jsonb_set(data, ?::text[], ?::jsonb)"
+func Set(doc any, path []string, value any) pgq.SQLizer {
+	return pgq.Expr("jsonb_set(?, ?::text[], ?)", operand(doc), path, jsonValue(value))
+}
+
+// BuildObject builds a jsonb object from the fields with the
+// jsonb_build_object function, in sorted order of the keys.
+//
+// Ex:
+//
+//	BuildObject(map[string]any{"id": pgq.Expr("u.id"), "name": "Alice"})
+//	// jsonb_build_object(?::text, u.id, ?::text, ?::jsonb)
+func BuildObject(fields map[string]any) pgq.SQLizer {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sql := "jsonb_build_object("
+	var args []any
+	for i, key := range keys {
+		if i > 0 {
+			sql += ", "
+		}
+		sql += "?::text, ?"
+		args = append(args, key, buildValue(fields[key]))
+	}
+	return pgq.Expr(sql+")", args...)
+}
+
+// PathQuery returns the items of doc returned by the jsonpath with the
+// jsonb_path_query function, which is set-returning.
+// vars are the values of the named variables of the jsonpath, and can be nil.
+//
+// Ex:
+//
+//	PathQuery("data", "$.items[*] ? (@.price > $min)", map[string]any{"min": 10})
+//	// jsonb_path_query(data, ?::jsonpath, ?::jsonb)
+func PathQuery(doc any, path string, vars map[string]any) pgq.SQLizer {
+	if vars == nil {
+		return pgq.Expr("jsonb_path_query(?, ?::jsonpath)", operand(doc), path)
+	}
+	return pgq.Expr("jsonb_path_query(?, ?::jsonpath, ?)", operand(doc), path, jsonValue(vars))
+}
+
+// operand returns doc as a SQLizer, where a string is a column or expression.
+func operand(doc any) pgq.SQLizer {
+	switch d := doc.(type) {
+	case pgq.SQLizer:
+		return d
+	case string:
+		return pgq.Expr(d)
+	}
+	return errSQLizer{fmt.Errorf("expected string or SQLizer, not %T", doc)}
+}
+
+// keyValue returns key as a text or integer argument, with an explicit cast to
+// choose between the overloads of the -> and ->> operators.
+func keyValue(key any) pgq.SQLizer {
+	switch k := key.(type) {
+	case pgq.SQLizer:
+		return k
+	case string:
+		return pgq.Expr("?::text", key)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return pgq.Expr("?::int", key)
+	}
+	return errSQLizer{fmt.Errorf("jsonb keys must be a string or an integer, not %T", key)}
+}
+
+// jsonValue returns value as a jsonb argument, or in parentheses if it is a
+// SQLizer, so it isn't split by the precedence of the operators.
+func jsonValue(value any) pgq.SQLizer {
+	if s, ok := value.(pgq.SQLizer); ok {
+		return pgq.Expr("(?)", s)
+	}
+	return jsonArg{value}
+}
+
+// buildValue is like jsonValue, but a SQLizer is a function argument and
+// doesn't need parentheses.
+func buildValue(value any) pgq.SQLizer {
+	if s, ok := value.(pgq.SQLizer); ok {
+		return s
+	}
+	return jsonArg{value}
+}
+
+// jsonArg is a value encoded as JSON and bound as a jsonb argument.
+type jsonArg struct {
+	value any
+}
+
+func (j jsonArg) SQL() (string, []any, error) {
+	b, err := json.Marshal(j.value)
+	if err != nil {
+		return "", nil, err
+	}
+	return "?::jsonb", []any{string(b)}, nil
+}
+
+// errSQLizer is a SQLizer that always fails with err.
+type errSQLizer struct {
+	err error
+}
+
+func (e errSQLizer) SQL() (string, []any, error) {
+	return "", nil, e.err
+}
@@ -0,0 +1,225 @@
+package jsonb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/henvic/pgq"
+)
+
+func TestJSONB(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        pgq.SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "get",
+			b:        Get("data", "address"),
+			wantSQL:  "data -> ?::text",
+			wantArgs: []any{"address"},
+		},
+		{
+			name:     "get_index",
+			b:        Get("data", -1),
+			wantSQL:  "data -> ?::int",
+			wantArgs: []any{-1},
+		},
+		{
+			name:     "get_text_chain",
+			b:        GetText(Get("data", "address"), "city"),
+			wantSQL:  "data -> ?::text ->> ?::text",
+			wantArgs: []any{"address", "city"},
+		},
+		{
+			name:     "get_expr_key",
+			b:        Get(pgq.Ident("Data"), pgq.Expr("lower(?)", "Key")),
+			wantSQL:  `"Data" -> lower(?)`,
+			wantArgs: []any{"Key"},
+		},
+		{
+			name:     "path",
+			b:        Path("data", "address", "city"),
+			wantSQL:  "data #> ?::text[]",
+			wantArgs: []any{[]string{"address", "city"}},
+		},
+		{
+			name:     "path_text",
+			b:        PathText("data", "tags", "0"),
+			wantSQL:  "data #>> ?::text[]",
+			wantArgs: []any{[]string{"tags", "0"}},
+		},
+		{
+			name:     "contains",
+			b:        Contains("data", map[string]any{"active": true, "roles": []string{"admin"}}),
+			wantSQL:  "data @> ?::jsonb",
+			wantArgs: []any{`{"active":true,"roles":["admin"]}`},
+		},
+		{
+			name:     "contains_raw",
+			b:        Contains("data", json.RawMessage(`{"a": 1}`)),
+			wantSQL:  "data @> ?::jsonb",
+			wantArgs: []any{`{"a":1}`},
+		},
+		{
+			name:     "contained_by_expr",
+			b:        ContainedBy("data", Get("defaults", "user")),
+			wantSQL:  "data <@ (defaults -> ?::text)",
+			wantArgs: []any{"user"},
+		},
+		{
+			name:     "has_key",
+			b:        HasKey("data", "email"),
+			wantSQL:  "data ?? ?",
+			wantArgs: []any{"email"},
+		},
+		{
+			name:     "has_any_key",
+			b:        HasAnyKey("data", "email", "phone"),
+			wantSQL:  "data ??| ?::text[]",
+			wantArgs: []any{[]string{"email", "phone"}},
+		},
+		{
+			name:     "has_all_keys",
+			b:        HasAllKeys("data", "email", "phone"),
+			wantSQL:  "data ??& ?::text[]",
+			wantArgs: []any{[]string{"email", "phone"}},
+		},
+		{
+			name:     "path_exists",
+			b:        PathExists("data", `$.tags[*] ? (@ == "go")`),
+			wantSQL:  "data @?? ?::jsonpath",
+			wantArgs: []any{`$.tags[*] ? (@ == "go")`},
+		},
+		{
+			name:     "path_match",
+			b:        PathMatch("data", "$.age >= 18"),
+			wantSQL:  "data @@ ?::jsonpath",
+			wantArgs: []any{"$.age >= 18"},
+		},
+		{
+			name:     "set",
+			b:        Set("data", []string{"address", "city"}, "Amsterdam"),
+			wantSQL:  "jsonb_set(data, ?::text[], ?::jsonb)",
+			wantArgs: []any{[]string{"address", "city"}, `"Amsterdam"`},
+		},
+		{
+			name:     "build_object",
+			b:        BuildObject(map[string]any{"name": "Alice", "id": pgq.Expr("u.id"), "tags": nil}),
+			wantSQL:  "jsonb_build_object(?::text, u.id, ?::text, ?::jsonb, ?::text, ?::jsonb)",
+			wantArgs: []any{"id", "name", `"Alice"`, "tags", "null"},
+		},
+		{
+			name:    "build_object_empty",
+			b:       BuildObject(nil),
+			wantSQL: "jsonb_build_object()",
+		},
+		{
+			name:     "path_query",
+			b:        PathQuery("data", "$.items[*]", nil),
+			wantSQL:  "jsonb_path_query(data, ?::jsonpath)",
+			wantArgs: []any{"$.items[*]"},
+		},
+		{
+			name:     "path_query_vars",
+			b:        PathQuery("data", "$.items[*] ? (@.price > $min)", map[string]any{"min": 10}),
+			wantSQL:  "jsonb_path_query(data, ?::jsonpath, ?::jsonb)",
+			wantArgs: []any{"$.items[*] ? (@.price > $min)", `{"min":10}`},
+		},
+		{
+			name: "select",
+			b: pgq.Select("id").
+				Column(pgq.Alias{Expr: GetText("data", "name"), As: "name"}).
+				From("users").
+				Where(HasKey("data", "email")).
+				Where(Contains("data", map[string]any{"active": true})).
+				Where("created_at > ?", "2024-01-01"),
+			wantSQL:  "SELECT id, (data ->> $1::text) AS name FROM users WHERE data ? $2 AND data @> $3::jsonb AND created_at > $4",
+			wantArgs: []any{"name", "email", `{"active":true}`, "2024-01-01"},
+		},
+		{
+			name: "update",
+			b: pgq.Update("users").
+				Set("data", Set("data", []string{"address", "city"}, "Amsterdam")).
+				Where(HasAnyKey("data", "address")).
+				Where(pgq.Eq{"id": 1}),
+			wantSQL:  "UPDATE users SET data = jsonb_set(data, $1::text[], $2::jsonb) WHERE data ?| $3::text[] AND id = $4",
+			wantArgs: []any{[]string{"address", "city"}, `"Amsterdam"`, []string{"address"}, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestJSONBErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    pgq.SQLizer
+		want string
+	}{
+		{
+			name: "doc",
+			b:    Get(1, "a"),
+			want: "expected string or SQLizer, not int",
+		},
+		{
+			name: "key",
+			b:    GetText("data", 1.5),
+			want: "jsonb keys must be a string or an integer, not float64",
+		},
+		{
+			name: "value",
+			b:    Contains("data", func() {}),
+			want: "json: unsupported type: func()",
+		},
+		{
+			name: "build_object",
+			b:    pgq.Select("id").Column(BuildObject(map[string]any{"a": make(chan int)})).From("t"),
+			want: "json: unsupported type: chan int",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleContains() {
+	sql, args, _ := pgq.Select("id").
+		Column(PathText("data", "address", "city")).
+		From("users").
+		Where(Contains("data", map[string]any{"active": true})).
+		Where(HasKey("data", "email")).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, data #>> $1::text[] FROM users WHERE data @> $2::jsonb AND data ? $3
+	// [[address city] {"active":true} email]
+}
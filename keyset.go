@@ -0,0 +1,352 @@
+package pgq
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidCursor is returned by KeysetBuilder.Cursor for cursor tokens that
+// weren't created by KeysetPage for the same keys, compared by their SQL and
+// direction.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// KeysetBuilder builds keyset pagination, also known as seek pagination, for
+// SelectBuilder.
+//
+// Instead of skipping rows with OFFSET, which gets slower the further the
+// page is, it filters the rows after, or before, the key values of the last
+// row of the previous page, so it can use an index on the keys.
+//
+// The keys must uniquely identify a row, such as by ending with the primary
+// key, and must not be null.
+//
+// Ex:
+//
+//	k, err := Keyset(Desc("created_at"), Desc("id")).Limit(20).Cursor(r.URL.Query().Get("cursor"))
+//	if err != nil {
+//		// bad request
+//	}
+//	var posts []Post
+//	// query Select("id", "title", "created_at").From("posts").Keyset(k) into posts
+//	page, err := KeysetPage(k, posts, func(p Post) []any { return []any{p.CreatedAt, p.ID} })
+//	// page.Rows, page.Next, page.Prev
+type KeysetBuilder struct {
+	keys     []Order
+	limit    uint64
+	values   []any
+	backward bool
+}
+
+// Keyset returns a new KeysetBuilder ordered by keys.
+func Keyset(keys ...Order) KeysetBuilder {
+	return KeysetBuilder{keys: keys}
+}
+
+// Limit sets the number of rows per page.
+//
+// The query fetches one more row to know if there are more pages.
+func (k KeysetBuilder) Limit(limit uint64) KeysetBuilder {
+	k.limit = limit
+	return k
+}
+
+// After pages forward, to the rows after the row with the key values.
+func (k KeysetBuilder) After(values ...any) KeysetBuilder {
+	k.values = values
+	k.backward = false
+	return k
+}
+
+// Before pages backward, to the rows before the row with the key values.
+func (k KeysetBuilder) Before(values ...any) KeysetBuilder {
+	k.values = values
+	k.backward = true
+	return k
+}
+
+// Cursor pages from a cursor token created by KeysetPage, forward or backward.
+// An empty token is the first page.
+//
+// The key values of the token are decoded as strings, int64, float64, or bool,
+// so values such as timestamps are passed to the query as text.
+//
+// Cursor returns ErrInvalidCursor if the token is invalid, or was created for
+// other keys.
+func (k KeysetBuilder) Cursor(token string) (KeysetBuilder, error) {
+	if token == "" {
+		return k, nil
+	}
+	keys, err := k.keysHash()
+	if err != nil {
+		return k, err
+	}
+	c, err := decodeCursor(token)
+	if err != nil || c.Keys != keys || len(c.Values) != len(k.keys) {
+		return k, ErrInvalidCursor
+	}
+	k.values = c.Values
+	k.backward = c.Backward
+	return k, nil
+}
+
+// orders returns the ORDER BY of the query, which is reversed when paging backward.
+func (k KeysetBuilder) orders() ([]Order, error) {
+	if len(k.keys) == 0 {
+		return nil, errors.New("keyset pagination must have at least one key")
+	}
+	if k.limit == 0 {
+		return nil, errors.New("keyset pagination must have a limit")
+	}
+	orders := make([]Order, 0, len(k.keys))
+	for _, key := range k.keys {
+		switch {
+		case key.expr == nil:
+			return nil, errors.New("keyset pagination keys must be created with Asc or Desc")
+		case key.using != "":
+			return nil, errors.New("keyset pagination keys cannot use USING")
+		case key.nulls != "":
+			return nil, errors.New("keyset pagination keys cannot have NULLS FIRST or NULLS LAST")
+		}
+		if k.backward {
+			if key.dir == "DESC" {
+				key.dir = "ASC"
+			} else {
+				key.dir = "DESC"
+			}
+		}
+		orders = append(orders, key)
+	}
+	return orders, nil
+}
+
+// keysetPredicate filters the rows after the key values in the order of the query.
+type keysetPredicate struct {
+	orders []Order
+	values []any
+}
+
+func (p keysetPredicate) SQL() (string, []any, error) {
+	return resolvedSQL(p)
+}
+
+func (p keysetPredicate) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if len(p.values) != len(p.orders) {
+		err = fmt.Errorf("keyset cursor must have %d values, got %d", len(p.orders), len(p.values))
+		return
+	}
+
+	var (
+		exprs    = make([]string, len(p.orders))
+		exprArgs = make([][]any, len(p.orders))
+		ops      = make([]string, len(p.orders))
+		mixed    bool
+	)
+	for i, o := range p.orders {
+		exprs[i], exprArgs[i], err = nestedSQL(o.expr)
+		if err != nil {
+			return
+		}
+		ops[i] = ">"
+		if o.dir == "DESC" {
+			ops[i] = "<"
+		}
+		mixed = mixed || ops[i] != ops[0]
+	}
+
+	if len(p.orders) == 1 {
+		sqlStr = exprs[0] + " " + ops[0] + " ?"
+		args = append(append(args, exprArgs[0]...), p.values[0])
+		return
+	}
+
+	// Same direction: (a, b) > (?, ?)
+	if !mixed {
+		for _, a := range exprArgs {
+			args = append(args, a...)
+		}
+		args = append(args, p.values...)
+		sqlStr = fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), ops[0], strings.TrimSuffix(strings.Repeat("?, ", len(exprs)), ", "))
+		return
+	}
+
+	// Mixed directions: (a > ? OR (a = ? AND b < ?))
+	sql := &bytes.Buffer{}
+	sql.WriteString("(")
+	for i := range p.orders {
+		if i > 0 {
+			sql.WriteString(" OR (")
+		}
+		for j := 0; j < i; j++ {
+			sql.WriteString(exprs[j] + " = ? AND ")
+			args = append(append(args, exprArgs[j]...), p.values[j])
+		}
+		sql.WriteString(exprs[i] + " " + ops[i] + " ?")
+		args = append(append(args, exprArgs[i]...), p.values[i])
+		if i > 0 {
+			sql.WriteString(")")
+		}
+	}
+	sql.WriteString(")")
+	sqlStr = sql.String()
+	return
+}
+
+// Keyset paginates the query with k, adding the WHERE condition for its
+// cursor, its ORDER BY, and LIMIT with one more row than the page size.
+//
+// The query cannot have its own ORDER BY, LIMIT, OFFSET, or FETCH.
+//
+// See KeysetBuilder and KeysetPage.
+func (b SelectBuilder) Keyset(k KeysetBuilder) SelectBuilder {
+	b.keyset = &k
+	return b
+}
+
+// applyKeyset returns b with the clauses of its keyset pagination.
+func (b SelectBuilder) applyKeyset() (SelectBuilder, error) {
+	k := *b.keyset
+	b.keyset = nil
+	if len(b.orderByParts) > 0 {
+		return b, errors.New("keyset pagination cannot be used with ORDER BY, as the keys define the order")
+	}
+	if b.limit != nil || b.offset != nil || b.fetch != nil {
+		return b, errors.New("keyset pagination cannot be used with LIMIT, OFFSET, or FETCH")
+	}
+	orders, err := k.orders()
+	if err != nil {
+		return b, err
+	}
+	if len(k.values) > 0 {
+		// Copy the slice, so the WHERE of b isn't changed.
+		b.whereParts = append(slices.Clip(b.whereParts), keysetPredicate{orders: orders, values: k.values})
+	}
+	return b.Sort(orders...).Limit(k.limit + 1), nil
+}
+
+// Page is a page of rows fetched with keyset pagination.
+type Page[T any] struct {
+	Rows []T
+
+	// Next is the cursor token of the next page, or empty on the last page.
+	Next string
+
+	// Prev is the cursor token of the previous page, or empty on the first page.
+	Prev string
+}
+
+// KeysetPage returns the page of the rows fetched by a query paginated with k,
+// removing the extra row fetched to know if there are more pages, and
+// restoring the order of the rows when paging backward.
+//
+// key returns the values of the keys of k for a row, in order, which are
+// encoded as JSON in the cursor tokens of the page.
+func KeysetPage[T any](k KeysetBuilder, rows []T, key func(row T) []any) (Page[T], error) {
+	if _, err := k.orders(); err != nil {
+		return Page[T]{}, err
+	}
+
+	more := uint64(len(rows)) > k.limit
+	if more {
+		rows = rows[:k.limit]
+	}
+	if k.backward {
+		rows = slices.Clone(rows)
+		slices.Reverse(rows)
+	}
+
+	page := Page[T]{Rows: rows}
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	hasNext, hasPrev := more, len(k.values) > 0
+	if k.backward {
+		hasNext, hasPrev = len(k.values) > 0, more
+	}
+
+	var err error
+	if hasNext {
+		if page.Next, err = k.encodeCursor(key(rows[len(rows)-1]), false); err != nil {
+			return Page[T]{}, err
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = k.encodeCursor(key(rows[0]), true); err != nil {
+			return Page[T]{}, err
+		}
+	}
+	return page, nil
+}
+
+type cursor struct {
+	// Keys is the hash of the keys, so a cursor isn't used with other keys.
+	Keys     uint32 `json:"k"`
+	Values   []any  `json:"v"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// keysHash returns a hash of the SQL and direction of the keys, with their
+// args, for the cursor tokens.
+func (k KeysetBuilder) keysHash() (uint32, error) {
+	h := fnv.New32a()
+	for _, key := range k.keys {
+		sql, args, err := nestedSQL(key)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(h, "%s %v\x00", sql, args)
+	}
+	return h.Sum32(), nil
+}
+
+func (k KeysetBuilder) encodeCursor(values []any, backward bool) (string, error) {
+	if len(values) != len(k.keys) {
+		return "", fmt.Errorf("keyset page key must return %d values, got %d", len(k.keys), len(values))
+	}
+	keys, err := k.keysHash()
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(cursor{Keys: keys, Values: values, Backward: backward})
+	if err != nil {
+		return "", fmt.Errorf("cannot encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(token string) (c cursor, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&c); err != nil {
+		return
+	}
+	if dec.More() {
+		err = errors.New("unexpected data after cursor")
+		return
+	}
+	for i, v := range c.Values {
+		switch v := v.(type) {
+		case string, bool:
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				c.Values[i] = n
+			} else if c.Values[i], err = v.Float64(); err != nil {
+				return c, err
+			}
+		default:
+			// Objects, arrays, and null aren't key values.
+			return c, fmt.Errorf("invalid cursor value %v", v)
+		}
+	}
+	return
+}
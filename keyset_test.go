@@ -0,0 +1,335 @@
+package pgq
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestKeyset(t *testing.T) {
+	t.Parallel()
+	posts := Select("id", "title").From("posts").Where("author_id = ?", 7)
+	newest := Keyset(Desc("created_at"), Desc("id")).Limit(20)
+
+	testCases := []struct {
+		name     string
+		b        SelectBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "first_page",
+			b:        posts.Keyset(newest),
+			wantSQL:  "SELECT id, title FROM posts WHERE author_id = $1 ORDER BY created_at DESC, id DESC LIMIT 21",
+			wantArgs: []any{7},
+		},
+		{
+			name:     "after",
+			b:        posts.Keyset(newest.After("2024-05-01T10:00:00Z", 42)),
+			wantSQL:  "SELECT id, title FROM posts WHERE author_id = $1 AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT 21",
+			wantArgs: []any{7, "2024-05-01T10:00:00Z", 42},
+		},
+		{
+			name:     "before",
+			b:        posts.Keyset(newest.Before("2024-05-01T10:00:00Z", 42)),
+			wantSQL:  "SELECT id, title FROM posts WHERE author_id = $1 AND (created_at, id) > ($2, $3) ORDER BY created_at ASC, id ASC LIMIT 21",
+			wantArgs: []any{7, "2024-05-01T10:00:00Z", 42},
+		},
+		{
+			name:     "single_key",
+			b:        Select("*").From("t").Keyset(Keyset(Asc("id")).Limit(10).After(5)),
+			wantSQL:  "SELECT * FROM t WHERE id > $1 ORDER BY id ASC LIMIT 11",
+			wantArgs: []any{5},
+		},
+		{
+			name: "mixed_directions",
+			b:    Select("*").From("t").Keyset(Keyset(Asc("name"), Desc("score"), Asc("id")).Limit(10).After("bob", 3, 9)),
+			wantSQL: "SELECT * FROM t WHERE (name > $1 OR (name = $2 AND score < $3) OR (name = $4 AND score = $5 AND id > $6)) " +
+				"ORDER BY name ASC, score DESC, id ASC LIMIT 11",
+			wantArgs: []any{"bob", "bob", 3, "bob", 3, 9},
+		},
+		{
+			name: "mixed_directions_before",
+			b:    Select("*").From("t").Keyset(Keyset(Asc("name"), Desc("id")).Limit(10).Before("bob", 9)),
+			wantSQL: "SELECT * FROM t WHERE (name < $1 OR (name = $2 AND id > $3)) " +
+				"ORDER BY name DESC, id ASC LIMIT 11",
+			wantArgs: []any{"bob", "bob", 9},
+		},
+		{
+			name:     "expr_key",
+			b:        Select("*").From("t").Keyset(Keyset(Desc(Expr("coalesce(updated_at, ?)", "epoch")), Asc(Ident("id"))).Limit(5).After("2024-01-01", 1)),
+			wantSQL:  `SELECT * FROM t WHERE (coalesce(updated_at, $1) < $2 OR (coalesce(updated_at, $3) = $4 AND "id" > $5)) ORDER BY coalesce(updated_at, $6) DESC, "id" ASC LIMIT 6`,
+			wantArgs: []any{"epoch", "2024-01-01", "epoch", "2024-01-01", 1, "epoch"},
+		},
+		{
+			name:    "union_first",
+			b:       Select("id").From("a").Keyset(Keyset(Asc("id")).Limit(5)).Union(Select("id").From("b")),
+			wantSQL: "(SELECT id FROM a ORDER BY id ASC LIMIT 6) UNION SELECT id FROM b",
+		},
+		{
+			name:     "union_last",
+			b:        Select("id").From("a").Union(Select("id").From("b").Keyset(Keyset(Asc("id")).Limit(5).After(3))),
+			wantSQL:  "SELECT id FROM a UNION (SELECT id FROM b WHERE id > $1 ORDER BY id ASC LIMIT 6)",
+			wantArgs: []any{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+
+	// The query the keyset was added to is unchanged.
+	if sql, _ := posts.MustSQL(); sql != "SELECT id, title FROM posts WHERE author_id = $1" {
+		t.Errorf("unexpected SQL: %q", sql)
+	}
+}
+
+func TestKeysetErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SelectBuilder
+		want string
+	}{
+		{
+			name: "no_keys",
+			b:    Select("*").From("t").Keyset(Keyset().Limit(10)),
+			want: "keyset pagination must have at least one key",
+		},
+		{
+			name: "no_limit",
+			b:    Select("*").From("t").Keyset(Keyset(Asc("id"))),
+			want: "keyset pagination must have a limit",
+		},
+		{
+			name: "zero_key",
+			b:    Select("*").From("t").Keyset(Keyset(Order{}).Limit(10)),
+			want: "keyset pagination keys must be created with Asc or Desc",
+		},
+		{
+			name: "using",
+			b:    Select("*").From("t").Keyset(Keyset(Asc("id").Using("<")).Limit(10)),
+			want: "keyset pagination keys cannot use USING",
+		},
+		{
+			name: "nulls",
+			b:    Select("*").From("t").Keyset(Keyset(Asc("id").NullsLast()).Limit(10)),
+			want: "keyset pagination keys cannot have NULLS FIRST or NULLS LAST",
+		},
+		{
+			name: "values",
+			b:    Select("*").From("t").Keyset(Keyset(Asc("a"), Asc("id")).Limit(10).After(1)),
+			want: "keyset cursor must have 2 values, got 1",
+		},
+		{
+			name: "order_by",
+			b:    Select("*").From("t").OrderBy("id").Keyset(Keyset(Asc("id")).Limit(10)),
+			want: "keyset pagination cannot be used with ORDER BY, as the keys define the order",
+		},
+		{
+			name: "limit",
+			b:    Select("*").From("t").Offset(10).Keyset(Keyset(Asc("id")).Limit(10)),
+			want: "keyset pagination cannot be used with LIMIT, OFFSET, or FETCH",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+type keysetRow struct {
+	Score int
+	ID    int64
+}
+
+func keysetRowKey(r keysetRow) []any {
+	return []any{r.Score, r.ID}
+}
+
+func TestKeysetPage(t *testing.T) {
+	t.Parallel()
+	k := Keyset(Desc("score"), Asc("id")).Limit(2)
+
+	// First page: one extra row means there is a next page.
+	page, err := KeysetPage(k, []keysetRow{{9, 1}, {8, 2}, {8, 3}}, keysetRowKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []keysetRow{{9, 1}, {8, 2}}; !reflect.DeepEqual(page.Rows, want) {
+		t.Errorf("wanted %v, got %v instead", want, page.Rows)
+	}
+	if page.Next == "" || page.Prev != "" {
+		t.Fatalf("expected only a next cursor, got %q and %q instead", page.Next, page.Prev)
+	}
+
+	next, err := k.Cursor(page.Next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sql, args, err := Select("*").From("t").Keyset(next).SQL()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if want := "SELECT * FROM t WHERE (score < $1 OR (score = $2 AND id > $3)) ORDER BY score DESC, id ASC LIMIT 3"; sql != want {
+		t.Errorf("expected SQL to be %q, got %q instead", want, sql)
+	}
+	if want := []any{int64(8), int64(8), int64(2)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("wanted %v, got %v instead", want, args)
+	}
+
+	// Last page: no extra row, but there is a previous page.
+	page, err = KeysetPage(next, []keysetRow{{8, 3}, {7, 4}}, keysetRowKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Next != "" || page.Prev == "" {
+		t.Fatalf("expected only a previous cursor, got %q and %q instead", page.Next, page.Prev)
+	}
+
+	prev, err := k.Cursor(page.Prev)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sql, args, err = Select("*").From("t").Keyset(prev).SQL()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if want := "SELECT * FROM t WHERE (score > $1 OR (score = $2 AND id < $3)) ORDER BY score ASC, id DESC LIMIT 3"; sql != want {
+		t.Errorf("expected SQL to be %q, got %q instead", want, sql)
+	}
+	if want := []any{int64(8), int64(8), int64(3)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("wanted %v, got %v instead", want, args)
+	}
+
+	// Paging backward, rows are fetched in reverse, and restored.
+	fetched := []keysetRow{{8, 2}, {9, 1}}
+	page, err = KeysetPage(prev, fetched, keysetRowKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []keysetRow{{9, 1}, {8, 2}}; !reflect.DeepEqual(page.Rows, want) {
+		t.Errorf("wanted %v, got %v instead", want, page.Rows)
+	}
+	if want := []keysetRow{{8, 2}, {9, 1}}; !reflect.DeepEqual(fetched, want) {
+		t.Errorf("expected fetched rows to be unchanged, got %v instead", fetched)
+	}
+	if page.Next == "" || page.Prev != "" {
+		t.Errorf("expected only a next cursor, got %q and %q instead", page.Next, page.Prev)
+	}
+
+	// Empty page.
+	page, err = KeysetPage(k, []keysetRow{}, keysetRowKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Rows) != 0 || page.Next != "" || page.Prev != "" {
+		t.Errorf("expected empty page, got %+v instead", page)
+	}
+}
+
+func TestKeysetPageErr(t *testing.T) {
+	t.Parallel()
+	_, err := KeysetPage(Keyset(Asc("id")), []int{1}, func(i int) []any { return []any{i} })
+	if want := "keyset pagination must have a limit"; err == nil || err.Error() != want {
+		t.Errorf("expected error to be %q, got %v instead", want, err)
+	}
+
+	_, err = KeysetPage(Keyset(Asc("a"), Asc("id")).Limit(1), []int{1, 2}, func(i int) []any { return []any{i} })
+	if want := "keyset page key must return 2 values, got 1"; err == nil || err.Error() != want {
+		t.Errorf("expected error to be %q, got %v instead", want, err)
+	}
+
+	_, err = KeysetPage(Keyset(Asc("id")).Limit(1), []int{1, 2}, func(i int) []any { return []any{make(chan int)} })
+	if want := "cannot encode cursor: json: unsupported type: chan int"; err == nil || err.Error() != want {
+		t.Errorf("expected error to be %q, got %v instead", want, err)
+	}
+}
+
+func TestKeysetCursor(t *testing.T) {
+	t.Parallel()
+	k := Keyset(Desc("created_at"), Asc("id"), Asc("ok"), Asc("ratio")).Limit(1)
+	page, err := KeysetPage(k, []int{1, 2}, func(i int) []any { return []any{"2024-05-01T10:00:00Z", i, true, 0.5} })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next, err := k.Cursor(page.Next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []any{"2024-05-01T10:00:00Z", int64(1), true, 0.5}; !reflect.DeepEqual(next.values, want) || next.backward {
+		t.Errorf("wanted %v forward, got %v (backward: %v) instead", want, next.values, next.backward)
+	}
+
+	same, err := k.Cursor("")
+	if err != nil || !reflect.DeepEqual(same, k) {
+		t.Errorf("expected empty cursor to be the first page, got %+v, %v instead", same, err)
+	}
+
+	for _, token := range []string{
+		"not base64!",
+		"bm90IGpzb24",                    // not json
+		"eyJ2IjpbMV19",                   // {"v":[1]}, too few values
+		"eyJ2IjpbbnVsbCwxLHRydWUsMV19",   // {"v":[null,1,true,1]}
+		"eyJ2IjpbW10sMSx0cnVlLDFdfQ",     // {"v":[[],1,true,1]}
+		"eyJ2IjpbIngiLDEsdHJ1ZSwxXX17fQ", // {"v":["x",1,true,1]}{}
+	} {
+		if _, err := k.Cursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v instead", token, err)
+		}
+	}
+
+	for _, other := range []KeysetBuilder{
+		Keyset(Desc("updated_at"), Asc("id"), Asc("ok"), Asc("ratio")),
+		Keyset(Asc("created_at"), Asc("id"), Asc("ok"), Asc("ratio")),
+		Keyset(Desc("created_at"), Asc(Expr("id + ?", 1)), Asc("ok"), Asc("ratio")),
+	} {
+		if _, err := other.Limit(1).Cursor(page.Next); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for keys %v, got %v instead", other.keys, err)
+		}
+	}
+}
+
+func ExampleKeysetPage() {
+	type post struct {
+		ID        int64
+		CreatedAt string
+	}
+	k := Keyset(Desc("created_at"), Desc("id")).Limit(2)
+
+	sql, args, _ := Select("id", "created_at").From("posts").Keyset(k).SQL()
+	fmt.Println(sql, args)
+
+	// Rows fetched with the query above.
+	rows := []post{{3, "2024-05-03"}, {2, "2024-05-02"}, {1, "2024-05-01"}}
+	page, _ := KeysetPage(k, rows, func(p post) []any { return []any{p.CreatedAt, p.ID} })
+	fmt.Println(page.Rows)
+
+	next, _ := k.Cursor(page.Next)
+	sql, args, _ = Select("id", "created_at").From("posts").Keyset(next).SQL()
+	fmt.Println(sql, args)
+	// Output:
+	// SELECT id, created_at FROM posts ORDER BY created_at DESC, id DESC LIMIT 3 []
+	// [{3 2024-05-03} {2 2024-05-02}]
+	// SELECT id, created_at FROM posts WHERE (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT 3 [2024-05-02 2]
+}
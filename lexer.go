@@ -0,0 +1,313 @@
+package pgq
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+
+	// tokenText is SQL to be copied as is.
+	tokenText
+
+	// tokenPlaceholder is a "?" placeholder.
+	tokenPlaceholder
+
+	// tokenEscape is an escaped "??", rendered as "?".
+	tokenEscape
+
+	// tokenBackRef is a reference to a previous placeholder. See backRefMarker.
+	tokenBackRef
+
+	// tokenName is a @name reference to a named parameter. See Named.
+	tokenName
+)
+
+type lexerMode int
+
+const (
+	modeCode lexerMode = iota
+	modeString
+	modeEscapeString
+	modeQuotedIdent
+	modeDollarQuote
+	modeLineComment
+	modeBlockComment
+)
+
+// lexer splits SQL into text and placeholders.
+//
+// A "?" is a placeholder unless it is inside a string literal, quoted
+// identifier, dollar-quoted string, or comment, or it follows an operand, such
+// as an identifier, a literal, or a closing parenthesis, in which case it is
+// the jsonb ? operator, like in data ? 'key', or part of the ?| and ?& operators.
+// The jsonb @? operator and OPERATOR(schema.op) constructs are operators.
+//
+// "??" is always an escaped "?", including inside literals and comments, so
+// queries written before "?" was recognized in context keep working.
+type lexer struct {
+	sql  string
+	pos  int
+	mode lexerMode
+
+	// operand is whether the last token can be followed by a binary operator.
+	operand bool
+
+	// depth of nested block comments.
+	depth int
+
+	// tag of the dollar-quoted string, including the dollar signs.
+	tag string
+
+	// named enables tokenName.
+	named bool
+}
+
+func newLexer(sql string) *lexer {
+	return &lexer{sql: sql}
+}
+
+// next returns the next token and its text.
+func (l *lexer) next() (tokenKind, string) {
+	start := l.pos
+	for l.pos < len(l.sql) {
+		kind, n := l.special()
+		if n == 0 {
+			l.advance()
+			continue
+		}
+		if l.pos > start {
+			return tokenText, l.sql[start:l.pos]
+		}
+		text := l.sql[l.pos : l.pos+n]
+		l.pos += n
+		if l.mode == modeCode {
+			// An escaped "??" in code is the jsonb operator, while the others are operands.
+			l.operand = kind != tokenEscape
+		}
+		return kind, text
+	}
+	if l.pos > start {
+		return tokenText, l.sql[start:l.pos]
+	}
+	return tokenEOF, ""
+}
+
+// special returns the kind and length of the token starting at the current
+// position if it isn't text, without consuming it.
+func (l *lexer) special() (tokenKind, int) {
+	rest := l.sql[l.pos:]
+	if strings.HasPrefix(rest, "??") {
+		return tokenEscape, 2
+	}
+	if l.mode != modeCode {
+		return tokenText, 0
+	}
+	switch c := rest[0]; {
+	case c == '?' && !l.operand:
+		return tokenPlaceholder, 1
+	case c == backRefMarker:
+		if end := strings.IndexByte(rest[1:], backRefMarker); end != -1 {
+			return tokenBackRef, end + 2
+		}
+		return tokenBackRef, len(rest)
+	case c == '@' && l.named && len(rest) > 1 && isIdentStart(rest[1]) &&
+		(l.pos == 0 || !isIdentChar(l.sql[l.pos-1]) && !isOperatorChar(l.sql[l.pos-1])):
+		n := 2
+		for n < len(rest) && isIdentChar(rest[n]) {
+			n++
+		}
+		return tokenName, n
+	}
+	return tokenText, 0
+}
+
+// advance consumes text, at least one byte.
+func (l *lexer) advance() {
+	rest := l.sql[l.pos:]
+	switch l.mode {
+	case modeString, modeQuotedIdent:
+		quote := byte('\'')
+		if l.mode == modeQuotedIdent {
+			quote = '"'
+		}
+		switch {
+		case rest[0] != quote:
+			l.pos++
+		case len(rest) > 1 && rest[1] == quote:
+			l.pos += 2
+		default:
+			l.pos++
+			l.mode = modeCode
+		}
+	case modeEscapeString:
+		switch {
+		case rest[0] == '\\' && len(rest) > 1:
+			l.pos += 2
+		case rest[0] != '\'':
+			l.pos++
+		case len(rest) > 1 && rest[1] == '\'':
+			l.pos += 2
+		default:
+			l.pos++
+			l.mode = modeCode
+		}
+	case modeDollarQuote:
+		if strings.HasPrefix(rest, l.tag) {
+			l.pos += len(l.tag)
+			l.mode = modeCode
+			return
+		}
+		l.pos++
+	case modeLineComment:
+		if rest[0] == '\n' {
+			l.mode = modeCode
+		}
+		l.pos++
+	case modeBlockComment:
+		switch {
+		case strings.HasPrefix(rest, "/*"):
+			l.depth++
+			l.pos += 2
+		case strings.HasPrefix(rest, "*/"):
+			l.depth--
+			l.pos += 2
+			if l.depth == 0 {
+				l.mode = modeCode
+			}
+		default:
+			l.pos++
+		}
+	default:
+		l.advanceCode(rest)
+	}
+}
+
+func (l *lexer) advanceCode(rest string) {
+	c := rest[0]
+	switch {
+	case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+		l.pos++
+	case strings.HasPrefix(rest, "--"):
+		l.mode = modeLineComment
+		l.pos += 2
+	case strings.HasPrefix(rest, "/*"):
+		l.mode = modeBlockComment
+		l.depth = 1
+		l.pos += 2
+	case c == '\'':
+		l.mode = modeString
+		l.operand = true
+		l.pos++
+	case c == '"':
+		l.mode = modeQuotedIdent
+		l.operand = true
+		l.pos++
+	case c == '$':
+		l.advanceDollar(rest)
+	case strings.HasPrefix(rest, "@?") && !strings.HasPrefix(rest, "@??"):
+		// The jsonb @? operator, unless it is written with an escaped "??".
+		l.operand = false
+		l.pos += 2
+	case isWordStart(c):
+		// A word after a "." is a qualified name, such as t.first, even if
+		// it is a keyword.
+		qualified := l.pos > 0 && l.sql[l.pos-1] == '.'
+		n := 1
+		for n < len(rest) && isWordChar(rest[n]) {
+			n++
+		}
+		word := rest[:n]
+		l.pos += n
+		if (word == "E" || word == "e") && n < len(rest) && rest[n] == '\'' {
+			l.mode = modeEscapeString
+			l.operand = true
+			l.pos++
+			return
+		}
+		if strings.EqualFold(word, "OPERATOR") && n < len(rest) && rest[n] == '(' {
+			// An OPERATOR(schema.op) construct is an operator, which can
+			// contain "?" in its name.
+			if end := strings.IndexByte(rest[n:], ')'); end != -1 {
+				l.pos += end + 1
+				l.operand = false
+				return
+			}
+		}
+		l.operand = qualified || !isKeyword(word)
+	case '0' <= c && c <= '9' || c == '.' && len(rest) > 1 && '0' <= rest[1] && rest[1] <= '9':
+		n := 1
+		for n < len(rest) && (isIdentChar(rest[n]) || rest[n] == '.') {
+			n++
+		}
+		l.pos += n
+		l.operand = true
+	case c == ')' || c == ']':
+		l.operand = true
+		l.pos++
+	default:
+		// Operators and punctuation, including the jsonb ? operator.
+		l.operand = false
+		l.pos++
+	}
+}
+
+// advanceDollar consumes a positional parameter such as $1, or the opening
+// tag of a dollar-quoted string such as $$ or $body$.
+func (l *lexer) advanceDollar(rest string) {
+	if len(rest) > 1 && '0' <= rest[1] && rest[1] <= '9' {
+		n := 2
+		for n < len(rest) && '0' <= rest[n] && rest[n] <= '9' {
+			n++
+		}
+		l.pos += n
+		l.operand = true
+		return
+	}
+	n := 1
+	if n < len(rest) && isWordStart(rest[n]) {
+		for n < len(rest) && isWordChar(rest[n]) && rest[n] != '$' {
+			n++
+		}
+	}
+	if n < len(rest) && rest[n] == '$' {
+		l.tag = rest[:n+1]
+		l.mode = modeDollarQuote
+		l.operand = true
+		l.pos += n + 1
+		return
+	}
+	l.operand = false
+	l.pos++
+}
+
+func isWordStart(c byte) bool {
+	return isIdentStart(c) || c >= 0x80
+}
+
+func isWordChar(c byte) bool {
+	return isIdentChar(c) || c == '$' || c >= 0x80
+}
+
+// keywords that can be followed by a value, so a "?" after them is a placeholder.
+var keywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "AS": true, "BETWEEN": true,
+	"BOTH": true, "BY": true, "CASE": true, "DISTINCT": true, "ELSE": true,
+	"ESCAPE": true, "EXCEPT": true, "FETCH": true, "FIRST": true, "FOR": true,
+	"FROM": true, "GROUPS": true, "HAVING": true, "ILIKE": true, "IN": true,
+	"INTERSECT": true, "IS": true, "LEADING": true, "LIKE": true, "LIMIT": true,
+	"NEXT": true, "NOT": true, "OFFSET": true, "ON": true, "OR": true,
+	"PLACING": true, "RANGE": true, "RETURN": true, "RETURNING": true, "ROWS": true,
+	"SELECT": true, "SET": true, "SIMILAR": true, "SOME": true, "SYMMETRIC": true,
+	"THEN": true, "TO": true, "TRAILING": true, "UNION": true, "USING": true,
+	"VALUES": true, "VARIADIC": true, "WHEN": true, "WHERE": true, "ZONE": true,
+}
+
+func isKeyword(word string) bool {
+	if len(word) > len("INTERSECT") {
+		return false
+	}
+	return keywords[strings.ToUpper(word)]
+}
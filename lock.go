@@ -0,0 +1,155 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	lockForUpdate      = "FOR UPDATE"
+	lockForNoKeyUpdate = "FOR NO KEY UPDATE"
+	lockForShare       = "FOR SHARE"
+	lockForKeyShare    = "FOR KEY SHARE"
+)
+
+// lockClause is a row-level locking clause of a SELECT statement.
+type lockClause struct {
+	strength   string
+	of         []string
+	noWait     bool
+	skipLocked bool
+}
+
+func (l lockClause) validate() error {
+	if l.strength == "" {
+		return errors.New("NOWAIT and SKIP LOCKED require a locking clause such as FOR UPDATE")
+	}
+	if l.noWait && l.skipLocked {
+		return fmt.Errorf("%s cannot have both NOWAIT and SKIP LOCKED", l.strength)
+	}
+	return nil
+}
+
+func (l lockClause) writeTo(sql *bytes.Buffer) {
+	sql.WriteString(l.strength)
+	if len(l.of) > 0 {
+		sql.WriteString(" OF ")
+		sql.WriteString(strings.Join(l.of, ", "))
+	}
+	if l.noWait {
+		sql.WriteString(" NOWAIT")
+	}
+	if l.skipLocked {
+		sql.WriteString(" SKIP LOCKED")
+	}
+}
+
+// validateLocks checks the locking clauses against the clauses PostgreSQL
+// doesn't allow them to be combined with.
+func (b SelectBuilder) validateLocks() error {
+	for _, l := range b.locks {
+		if err := l.validate(); err != nil {
+			return err
+		}
+	}
+	if len(b.locks) == 0 {
+		return nil
+	}
+	strength := b.locks[0].strength
+	switch {
+	case b.hasDistinct():
+		return fmt.Errorf("%s cannot be used with DISTINCT", strength)
+	case len(b.groupBys) > 0:
+		return fmt.Errorf("%s cannot be used with GROUP BY", strength)
+	case len(b.havingParts) > 0:
+		return fmt.Errorf("%s cannot be used with HAVING", strength)
+	case len(b.setOps) > 0:
+		return fmt.Errorf("%s cannot be used with UNION, INTERSECT, or EXCEPT", strength)
+	}
+	return nil
+}
+
+func (b SelectBuilder) hasDistinct() bool {
+	if len(b.distinctOn) > 0 {
+		return true
+	}
+	for _, option := range b.options {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(option)), "DISTINCT") {
+			return true
+		}
+	}
+	return false
+}
+
+func (b SelectBuilder) appendLocksToSQL(sql *bytes.Buffer) {
+	for _, l := range b.locks {
+		sql.WriteString(" ")
+		l.writeTo(sql)
+	}
+}
+
+func (b SelectBuilder) lock(strength string, of []string) SelectBuilder {
+	b.locks = append(b.locks, lockClause{strength: strength, of: of})
+	return b
+}
+
+// ForUpdate adds a FOR UPDATE locking clause to the query.
+// If tables are given, only rows from these tables are locked (FOR UPDATE OF).
+//
+// Locking clauses are rendered after LIMIT and OFFSET, and several of them
+// can be used in the same query.
+//
+// Ex:
+//
+//	Select("*").From("jobs").Where("status = ?", "pending").Limit(10).ForUpdate().SkipLocked()
+func (b SelectBuilder) ForUpdate(tables ...string) SelectBuilder {
+	return b.lock(lockForUpdate, tables)
+}
+
+// ForNoKeyUpdate adds a FOR NO KEY UPDATE locking clause to the query.
+//
+// See ForUpdate.
+func (b SelectBuilder) ForNoKeyUpdate(tables ...string) SelectBuilder {
+	return b.lock(lockForNoKeyUpdate, tables)
+}
+
+// ForShare adds a FOR SHARE locking clause to the query.
+//
+// See ForUpdate.
+func (b SelectBuilder) ForShare(tables ...string) SelectBuilder {
+	return b.lock(lockForShare, tables)
+}
+
+// ForKeyShare adds a FOR KEY SHARE locking clause to the query.
+//
+// See ForUpdate.
+func (b SelectBuilder) ForKeyShare(tables ...string) SelectBuilder {
+	return b.lock(lockForKeyShare, tables)
+}
+
+// NoWait adds NOWAIT to the last locking clause of the query, so it fails
+// instead of waiting for locked rows.
+func (b SelectBuilder) NoWait() SelectBuilder {
+	return b.lockWait(func(l *lockClause) { l.noWait = true })
+}
+
+// SkipLocked adds SKIP LOCKED to the last locking clause of the query, so
+// rows that cannot be locked immediately are skipped.
+func (b SelectBuilder) SkipLocked() SelectBuilder {
+	return b.lockWait(func(l *lockClause) { l.skipLocked = true })
+}
+
+func (b SelectBuilder) lockWait(set func(*lockClause)) SelectBuilder {
+	// Copy the locking clauses to avoid changing builders sharing the same backing array.
+	locks := make([]lockClause, len(b.locks), len(b.locks)+1)
+	copy(locks, b.locks)
+	if len(locks) == 0 {
+		// Reported as an error by SQL.
+		locks = append(locks, lockClause{})
+	}
+	set(&locks[len(locks)-1])
+	b.locks = locks
+	return b
+}
@@ -0,0 +1,167 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuilderLocking(t *testing.T) {
+	t.Parallel()
+	jobs := Select("*").From("jobs").Where("status = ?", "pending")
+
+	testCases := []struct {
+		name     string
+		b        SelectBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "for_update",
+			b:        jobs.ForUpdate(),
+			wantSQL:  "SELECT * FROM jobs WHERE status = $1 FOR UPDATE",
+			wantArgs: []any{"pending"},
+		},
+		{
+			name:     "for_update_skip_locked",
+			b:        jobs.OrderBy("id").Limit(10).Offset(5).ForUpdate().SkipLocked(),
+			wantSQL:  "SELECT * FROM jobs WHERE status = $1 ORDER BY id LIMIT 10 OFFSET 5 FOR UPDATE SKIP LOCKED",
+			wantArgs: []any{"pending"},
+		},
+		{
+			name:     "for_no_key_update_nowait",
+			b:        jobs.ForNoKeyUpdate().NoWait(),
+			wantSQL:  "SELECT * FROM jobs WHERE status = $1 FOR NO KEY UPDATE NOWAIT",
+			wantArgs: []any{"pending"},
+		},
+		{
+			name: "multiple_of",
+			b: Select("*").From("jobs j").Join("workers w ON w.id = j.worker_id").
+				ForUpdate("j").SkipLocked().
+				ForShare("w", "x").NoWait().
+				Suffix("/* worker */"),
+			wantSQL: "SELECT * FROM jobs j JOIN workers w ON w.id = j.worker_id FOR UPDATE OF j SKIP LOCKED FOR SHARE OF w, x NOWAIT /* worker */",
+		},
+		{
+			name:    "for_key_share",
+			b:       Select("id").From("t").ForKeyShare(),
+			wantSQL: "SELECT id FROM t FOR KEY SHARE",
+		},
+		{
+			name:     "subquery",
+			b:        Select("*").FromSelect(jobs.Limit(1).ForUpdate().SkipLocked(), "j").Where("j.id > ?", 2),
+			wantSQL:  "SELECT * FROM (SELECT * FROM jobs WHERE status = $1 LIMIT 1 FOR UPDATE SKIP LOCKED) AS j WHERE j.id > $2",
+			wantArgs: []any{"pending", 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestSelectBuilderLockingImmutable(t *testing.T) {
+	t.Parallel()
+	b := Select("*").From("t").ForUpdate()
+	nowait := b.NoWait()
+	skip := b.SkipLocked()
+
+	if sql, _ := nowait.MustSQL(); sql != "SELECT * FROM t FOR UPDATE NOWAIT" {
+		t.Errorf("unexpected SQL: %q", sql)
+	}
+	if sql, _ := skip.MustSQL(); sql != "SELECT * FROM t FOR UPDATE SKIP LOCKED" {
+		t.Errorf("unexpected SQL: %q", sql)
+	}
+	if sql, _ := b.MustSQL(); sql != "SELECT * FROM t FOR UPDATE" {
+		t.Errorf("unexpected SQL: %q", sql)
+	}
+}
+
+func TestSelectBuilderLockingErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SelectBuilder
+		want string
+	}{
+		{
+			name: "no_lock",
+			b:    Select("*").From("t").SkipLocked(),
+			want: "NOWAIT and SKIP LOCKED require a locking clause such as FOR UPDATE",
+		},
+		{
+			name: "nowait_skip_locked",
+			b:    Select("*").From("t").ForShare().NoWait().SkipLocked(),
+			want: "FOR SHARE cannot have both NOWAIT and SKIP LOCKED",
+		},
+		{
+			name: "distinct",
+			b:    Select("a").Distinct().From("t").ForUpdate(),
+			want: "FOR UPDATE cannot be used with DISTINCT",
+		},
+		{
+			name: "group_by",
+			b:    Select("a").From("t").GroupBy("a").ForNoKeyUpdate(),
+			want: "FOR NO KEY UPDATE cannot be used with GROUP BY",
+		},
+		{
+			name: "having",
+			b:    Select("count(*)").From("t").Having("count(*) > 1").ForKeyShare(),
+			want: "FOR KEY SHARE cannot be used with HAVING",
+		},
+		{
+			name: "set_operation",
+			b:    Union(Select("a").From("t"), Select("a").From("u")).ForUpdate(),
+			want: "FOR UPDATE cannot be used with UNION, INTERSECT, or EXCEPT",
+		},
+		{
+			name: "set_operation_operand",
+			b:    Union(Select("a").From("t"), Select("a").From("u").ForShare()),
+			want: "FOR SHARE cannot be used with UNION, INTERSECT, or EXCEPT",
+		},
+		{
+			name: "set_operation_first_operand",
+			b:    Select("a").From("t").ForUpdate().Union(Select("a").From("u")),
+			want: "FOR UPDATE cannot be used with UNION, INTERSECT, or EXCEPT",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleSelectBuilder_ForUpdate() {
+	sql, args, _ := Select("id", "payload").
+		From("jobs").
+		Where("status = ?", "pending").
+		OrderBy("id").
+		Limit(10).
+		ForUpdate().
+		SkipLocked().
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, payload FROM jobs WHERE status = $1 ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED
+	// [pending]
+}
@@ -0,0 +1,424 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MergeBuilder builds SQL MERGE statements.
+//
+// MERGE requires PostgreSQL 15 or later, and RETURNING requires PostgreSQL 17 or later.
+type MergeBuilder struct {
+	prefixes  []SQLizer
+	ctes      []CTE
+	into      string
+	using     SQLizer
+	onParts   []SQLizer
+	whenParts []mergeWhen
+	returning []SQLizer
+	suffixes  []SQLizer
+}
+
+const (
+	mergeMatched            = "MATCHED"
+	mergeNotMatched         = "NOT MATCHED"
+	mergeNotMatchedBySource = "NOT MATCHED BY SOURCE"
+
+	mergeActionUpdate    = "UPDATE"
+	mergeActionInsert    = "INSERT"
+	mergeActionDelete    = "DELETE"
+	mergeActionDoNothing = "DO NOTHING"
+)
+
+// mergeWhen is a "WHEN [NOT] MATCHED [AND condition] THEN action" clause.
+type mergeWhen struct {
+	kind   string
+	cond   SQLizer
+	action MergeAction
+}
+
+// SQL builds the query into a SQL string and bound args.
+func (b MergeBuilder) SQL() (sqlStr string, args []any, err error) {
+	sqlStr, args, err = b.unfinalizedSQL()
+	if err != nil {
+		return
+	}
+
+	sqlStr, err = dollarPlaceholder(sqlStr)
+	return
+}
+
+func (b MergeBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if b.into == "" {
+		err = errors.New("merge statements must specify a target table")
+		return
+	}
+	if b.using == nil {
+		err = errors.New("merge statements must specify a data source")
+		return
+	}
+	if len(b.onParts) == 0 {
+		err = errors.New("merge statements must specify a join condition")
+		return
+	}
+	if len(b.whenParts) == 0 {
+		err = errors.New("merge statements must have at least one WHEN clause")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	if len(b.prefixes) > 0 {
+		args, err = appendSQL(b.prefixes, sql, " ", args)
+		if err != nil {
+			return
+		}
+
+		sql.WriteString(" ")
+	}
+
+	args, err = appendCTEs(b.ctes, sql, args)
+	if err != nil {
+		return
+	}
+
+	sql.WriteString("MERGE INTO ")
+	sql.WriteString(b.into)
+
+	sql.WriteString(" USING ")
+	args, err = appendSQL([]SQLizer{b.using}, sql, "", args)
+	if err != nil {
+		return
+	}
+
+	sql.WriteString(" ON ")
+	args, err = appendSQL(b.onParts, sql, " AND ", args)
+	if err != nil {
+		return
+	}
+
+	for _, w := range b.whenParts {
+		switch {
+		case w.kind == mergeNotMatched && w.action.verb != mergeActionInsert && w.action.verb != mergeActionDoNothing:
+			err = fmt.Errorf("WHEN %s clauses can only INSERT or DO NOTHING", w.kind)
+			return
+		case w.kind != mergeNotMatched && w.action.verb == mergeActionInsert:
+			err = fmt.Errorf("WHEN %s clauses cannot INSERT", w.kind)
+			return
+		}
+
+		sql.WriteString(" WHEN ")
+		sql.WriteString(w.kind)
+		if w.cond != nil {
+			sql.WriteString(" AND ")
+			args, err = appendSQL([]SQLizer{w.cond}, sql, "", args)
+			if err != nil {
+				return
+			}
+		}
+		sql.WriteString(" THEN ")
+		args, err = appendSQL([]SQLizer{w.action}, sql, "", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.returning) > 0 {
+		sql.WriteString(" RETURNING ")
+		args, err = appendSQL(b.returning, sql, ", ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.suffixes) > 0 {
+		sql.WriteString(" ")
+		args, err = appendSQL(b.suffixes, sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// MustSQL builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b MergeBuilder) MustSQL() (string, []any) {
+	sql, args, err := b.SQL()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Prefix adds an expression to the beginning of the query
+func (b MergeBuilder) Prefix(sql string, args ...any) MergeBuilder {
+	return b.PrefixExpr(Expr(sql, args...))
+}
+
+// PrefixExpr adds an expression to the very beginning of the query
+func (b MergeBuilder) PrefixExpr(expr SQLizer) MergeBuilder {
+	b.prefixes = append(b.prefixes, expr)
+	return b
+}
+
+// With adds a common table expression to the WITH clause of the query.
+func (b MergeBuilder) With(name string, expr SQLizer) MergeBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr})
+}
+
+// WithRecursive adds a common table expression to the WITH clause of the query,
+// turning it into WITH RECURSIVE.
+func (b MergeBuilder) WithRecursive(name string, expr SQLizer) MergeBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr, Recursive: true})
+}
+
+// WithCTE adds a common table expression to the WITH clause of the query.
+func (b MergeBuilder) WithCTE(cte CTE) MergeBuilder {
+	b.ctes = append(b.ctes, cte)
+	return b
+}
+
+// Into sets the target table of the query, optionally followed by an alias.
+func (b MergeBuilder) Into(into string) MergeBuilder {
+	b.into = into
+	return b
+}
+
+// Using sets the data source of the query, optionally followed by an alias.
+func (b MergeBuilder) Using(source string) MergeBuilder {
+	b.using = newPart(source)
+	return b
+}
+
+// UsingSelect sets the data source of the query similar to Using, but takes a Select statement.
+func (b MergeBuilder) UsingSelect(source SelectBuilder, alias string) MergeBuilder {
+	b.using = Alias{Expr: source, As: alias}
+	return b
+}
+
+// On adds an expression to the join condition of the query.
+//
+// Expressions are ANDed together in the generated SQL.
+//
+// See SelectBuilder.Where for the accepted types.
+func (b MergeBuilder) On(pred any, args ...any) MergeBuilder {
+	b.onParts = append(b.onParts, newWherePart(pred, args...))
+	return b
+}
+
+// WhenMatched adds a "WHEN MATCHED THEN action" clause to the query.
+//
+// The action must be MergeUpdate, MergeDelete, or MergeDoNothing.
+func (b MergeBuilder) WhenMatched(action MergeAction) MergeBuilder {
+	return b.when(mergeMatched, nil, action)
+}
+
+// WhenMatchedAnd adds a "WHEN MATCHED AND condition THEN action" clause to the query.
+//
+// The condition is a SQLizer, such as Expr("s.qty > ?", 0) or Eq.
+func (b MergeBuilder) WhenMatchedAnd(cond SQLizer, action MergeAction) MergeBuilder {
+	return b.when(mergeMatched, cond, action)
+}
+
+// WhenNotMatched adds a "WHEN NOT MATCHED THEN action" clause to the query.
+//
+// The action must be MergeInsert or MergeDoNothing.
+func (b MergeBuilder) WhenNotMatched(action MergeAction) MergeBuilder {
+	return b.when(mergeNotMatched, nil, action)
+}
+
+// WhenNotMatchedAnd adds a "WHEN NOT MATCHED AND condition THEN action" clause to the query.
+//
+// See WhenMatchedAnd.
+func (b MergeBuilder) WhenNotMatchedAnd(cond SQLizer, action MergeAction) MergeBuilder {
+	return b.when(mergeNotMatched, cond, action)
+}
+
+// WhenNotMatchedBySource adds a "WHEN NOT MATCHED BY SOURCE THEN action" clause to the query.
+// It requires PostgreSQL 17 or later.
+//
+// The action must be MergeUpdate, MergeDelete, or MergeDoNothing.
+func (b MergeBuilder) WhenNotMatchedBySource(action MergeAction) MergeBuilder {
+	return b.when(mergeNotMatchedBySource, nil, action)
+}
+
+// WhenNotMatchedBySourceAnd adds a "WHEN NOT MATCHED BY SOURCE AND condition THEN action" clause to the query.
+//
+// See WhenMatchedAnd.
+func (b MergeBuilder) WhenNotMatchedBySourceAnd(cond SQLizer, action MergeAction) MergeBuilder {
+	return b.when(mergeNotMatchedBySource, cond, action)
+}
+
+func (b MergeBuilder) when(kind string, cond SQLizer, action MergeAction) MergeBuilder {
+	b.whenParts = append(b.whenParts, mergeWhen{kind: kind, cond: cond, action: action})
+	return b
+}
+
+// Returning adds RETURNING expressions to the query.
+func (b MergeBuilder) Returning(columns ...string) MergeBuilder {
+	parts := make([]SQLizer, 0, len(columns))
+	for _, col := range columns {
+		parts = append(parts, newPart(col))
+	}
+	b.returning = append(b.returning, parts...)
+	return b
+}
+
+// Suffix adds an expression to the end of the query
+func (b MergeBuilder) Suffix(sql string, args ...any) MergeBuilder {
+	return b.SuffixExpr(Expr(sql, args...))
+}
+
+// SuffixExpr adds an expression to the end of the query
+func (b MergeBuilder) SuffixExpr(expr SQLizer) MergeBuilder {
+	b.suffixes = append(b.suffixes, expr)
+	return b
+}
+
+// MergeAction is the action of a WHEN clause of a MERGE statement.
+//
+// Create it with MergeUpdate, MergeInsert, MergeDelete, or MergeDoNothing.
+type MergeAction struct {
+	verb          string
+	setClauses    []setClause
+	columns       []string
+	values        []any
+	defaultValues bool
+}
+
+// MergeUpdate returns an UPDATE SET action. Add assignments with Set or SetMap.
+func MergeUpdate() MergeAction {
+	return MergeAction{verb: mergeActionUpdate}
+}
+
+// MergeInsert returns an INSERT action with optional column names.
+// Set the inserted values with Values, or use DefaultValues.
+func MergeInsert(columns ...string) MergeAction {
+	return MergeAction{verb: mergeActionInsert, columns: columns}
+}
+
+// MergeDelete returns a DELETE action.
+func MergeDelete() MergeAction {
+	return MergeAction{verb: mergeActionDelete}
+}
+
+// MergeDoNothing returns a DO NOTHING action.
+func MergeDoNothing() MergeAction {
+	return MergeAction{verb: mergeActionDoNothing}
+}
+
+// Set adds an assignment to an UPDATE action.
+func (a MergeAction) Set(column string, value any) MergeAction {
+	a.setClauses = append(a.setClauses, setClause{column: column, value: value})
+	return a
+}
+
+// SetMap is a convenience method which calls .Set for each key/value pair in clauses.
+func (a MergeAction) SetMap(clauses map[string]any) MergeAction {
+	for _, key := range getSortedKeys(clauses) {
+		a = a.Set(key, clauses[key])
+	}
+	return a
+}
+
+// Values sets the values of an INSERT action.
+func (a MergeAction) Values(values ...any) MergeAction {
+	a.values = values
+	return a
+}
+
+// DefaultValues makes an INSERT action use DEFAULT VALUES.
+func (a MergeAction) DefaultValues() MergeAction {
+	a.defaultValues = true
+	return a
+}
+
+// SQL returns the SQL of the action.
+func (a MergeAction) SQL() (string, []any, error) {
+	return resolvedSQL(a)
+}
+
+func (a MergeAction) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if len(a.setClauses) > 0 && a.verb != mergeActionUpdate {
+		err = errors.New("merge Set can only be used with UPDATE actions")
+		return
+	}
+	if (len(a.values) > 0 || a.defaultValues) && a.verb != mergeActionInsert {
+		err = errors.New("merge Values can only be used with INSERT actions")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	switch a.verb {
+	case mergeActionUpdate:
+		if len(a.setClauses) == 0 {
+			err = errors.New("merge UPDATE actions must have at least one Set clause")
+			return
+		}
+		sql.WriteString("UPDATE SET ")
+		args, err = appendSetClauses(a.setClauses, sql, args)
+		if err != nil {
+			return
+		}
+	case mergeActionInsert:
+		args, err = a.appendInsertToSQL(sql, args)
+		if err != nil {
+			return
+		}
+	case mergeActionDelete, mergeActionDoNothing:
+		sql.WriteString(a.verb)
+	default:
+		err = errors.New("merge actions must be created with MergeUpdate, MergeInsert, MergeDelete, or MergeDoNothing")
+		return
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+func (a MergeAction) appendInsertToSQL(sql *bytes.Buffer, args []any) ([]any, error) {
+	if a.defaultValues {
+		if len(a.columns) > 0 || len(a.values) > 0 {
+			return nil, errors.New("merge INSERT DEFAULT VALUES cannot have columns or values")
+		}
+		sql.WriteString("INSERT DEFAULT VALUES")
+		return args, nil
+	}
+	if len(a.values) == 0 {
+		return nil, errors.New("merge INSERT actions must have values")
+	}
+	if len(a.columns) > 0 && len(a.columns) != len(a.values) {
+		return nil, fmt.Errorf("merge INSERT action has %d columns but %d values", len(a.columns), len(a.values))
+	}
+
+	sql.WriteString("INSERT ")
+	if len(a.columns) > 0 {
+		sql.WriteString("(")
+		sql.WriteString(strings.Join(a.columns, ", "))
+		sql.WriteString(") ")
+	}
+	sql.WriteString("VALUES (")
+	for i, val := range a.values {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		if vs, ok := val.(SQLizer); ok {
+			vsql, vargs, err := nestedSQL(vs)
+			if err != nil {
+				return nil, err
+			}
+			sql.WriteString(vsql)
+			args = append(args, vargs...)
+		} else {
+			sql.WriteString("?")
+			args = append(args, val)
+		}
+	}
+	sql.WriteString(")")
+	return args, nil
+}
@@ -0,0 +1,211 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMergeBuilderSQL(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        MergeBuilder
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "table_source",
+			b: Merge("customer_account ca").
+				Using("recent_transactions t").
+				On("t.customer_id = ca.customer_id").
+				WhenMatched(MergeUpdate().Set("balance", Expr("balance + t.transaction_value"))).
+				WhenNotMatched(MergeInsert("customer_id", "balance").Values(Expr("t.customer_id"), Expr("t.transaction_value"))),
+			wantSQL: "MERGE INTO customer_account ca USING recent_transactions t ON t.customer_id = ca.customer_id " +
+				"WHEN MATCHED THEN UPDATE SET balance = balance + t.transaction_value " +
+				"WHEN NOT MATCHED THEN INSERT (customer_id, balance) VALUES (t.customer_id, t.transaction_value)",
+		},
+		{
+			name: "select_source_conditional",
+			b: Merge("wines w").
+				Prefix("WITH prefix AS ?", 0).
+				UsingSelect(Select("*").From("wine_stock_changes").Where("batch = ?", 1), "s").
+				On("s.winename = w.winename").
+				On(Eq{"w.region": "rhone"}).
+				WhenNotMatchedAnd(Expr("s.stock_delta > ?", 0), MergeInsert().Values(Expr("s.winename"), Expr("s.stock_delta"))).
+				WhenMatchedAnd(Expr("w.stock + s.stock_delta > ?", 0), MergeUpdate().Set("stock", Expr("w.stock + s.stock_delta")).Set("note", "restocked")).
+				WhenMatched(MergeDelete()).
+				WhenNotMatched(MergeDoNothing()).
+				Suffix("RETURNING ?", 5),
+			wantSQL: "WITH prefix AS $1 MERGE INTO wines w " +
+				"USING (SELECT * FROM wine_stock_changes WHERE batch = $2) AS s " +
+				"ON s.winename = w.winename AND w.region = $3 " +
+				"WHEN NOT MATCHED AND s.stock_delta > $4 THEN INSERT VALUES (s.winename, s.stock_delta) " +
+				"WHEN MATCHED AND w.stock + s.stock_delta > $5 THEN UPDATE SET stock = w.stock + s.stock_delta, note = $6 " +
+				"WHEN MATCHED THEN DELETE " +
+				"WHEN NOT MATCHED THEN DO NOTHING " +
+				"RETURNING $7",
+			wantArgs: []any{0, 1, "rhone", 0, 0, "restocked", 5},
+		},
+		{
+			name: "by_source_returning",
+			b: Merge("t").
+				With("src", Select("id", "v").From("staging").Where("k = ?", 1)).
+				Using("src s").
+				On("s.id = t.id").
+				WhenMatched(MergeUpdate().SetMap(map[string]any{"v": Expr("s.v"), "seen": true})).
+				WhenNotMatched(MergeInsert("id", "v").Values(Expr("s.id"), 2)).
+				WhenNotMatchedBySourceAnd(Lt{"t.updated_at": "2024-01-01"}, MergeDelete()).
+				WhenNotMatchedBySource(MergeUpdate().Set("stale", true)).
+				Returning("merge_action()", "t.*"),
+			wantSQL: "WITH src AS (SELECT id, v FROM staging WHERE k = $1) " +
+				"MERGE INTO t USING src s ON s.id = t.id " +
+				"WHEN MATCHED THEN UPDATE SET seen = $2, v = s.v " +
+				"WHEN NOT MATCHED THEN INSERT (id, v) VALUES (s.id, $3) " +
+				"WHEN NOT MATCHED BY SOURCE AND t.updated_at < $4 THEN DELETE " +
+				"WHEN NOT MATCHED BY SOURCE THEN UPDATE SET stale = $5 " +
+				"RETURNING merge_action(), t.*",
+			wantArgs: []any{1, true, 2, "2024-01-01", true},
+		},
+		{
+			name: "default_values",
+			b: Statement().Merge("t").
+				Using("s").
+				On("s.id = t.id").
+				WhenNotMatched(MergeInsert().DefaultValues()),
+			wantSQL: "MERGE INTO t USING s ON s.id = t.id WHEN NOT MATCHED THEN INSERT DEFAULT VALUES",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestMergeBuilderSQLErr(t *testing.T) {
+	t.Parallel()
+	valid := Merge("t").Using("s").On("s.id = t.id")
+	testCases := []struct {
+		name string
+		b    MergeBuilder
+		want string
+	}{
+		{
+			name: "no_target",
+			b:    Merge("").Using("s").On("true").WhenMatched(MergeDelete()),
+			want: "merge statements must specify a target table",
+		},
+		{
+			name: "no_source",
+			b:    Merge("t").On("true").WhenMatched(MergeDelete()),
+			want: "merge statements must specify a data source",
+		},
+		{
+			name: "no_join_condition",
+			b:    Merge("t").Using("s").WhenMatched(MergeDelete()),
+			want: "merge statements must specify a join condition",
+		},
+		{
+			name: "no_when",
+			b:    valid,
+			want: "merge statements must have at least one WHEN clause",
+		},
+		{
+			name: "matched_insert",
+			b:    valid.WhenMatched(MergeInsert().Values(1)),
+			want: "WHEN MATCHED clauses cannot INSERT",
+		},
+		{
+			name: "not_matched_by_source_insert",
+			b:    valid.WhenNotMatchedBySource(MergeInsert().Values(1)),
+			want: "WHEN NOT MATCHED BY SOURCE clauses cannot INSERT",
+		},
+		{
+			name: "not_matched_update",
+			b:    valid.WhenNotMatched(MergeUpdate().Set("a", 1)),
+			want: "WHEN NOT MATCHED clauses can only INSERT or DO NOTHING",
+		},
+		{
+			name: "update_without_set",
+			b:    valid.WhenMatched(MergeUpdate()),
+			want: "merge UPDATE actions must have at least one Set clause",
+		},
+		{
+			name: "delete_with_set",
+			b:    valid.WhenMatched(MergeDelete().Set("a", 1)),
+			want: "merge Set can only be used with UPDATE actions",
+		},
+		{
+			name: "do_nothing_with_values",
+			b:    valid.WhenNotMatched(MergeDoNothing().Values(1)),
+			want: "merge Values can only be used with INSERT actions",
+		},
+		{
+			name: "insert_without_values",
+			b:    valid.WhenNotMatched(MergeInsert("a")),
+			want: "merge INSERT actions must have values",
+		},
+		{
+			name: "insert_values_mismatch",
+			b:    valid.WhenNotMatched(MergeInsert("a", "b").Values(1)),
+			want: "merge INSERT action has 2 columns but 1 values",
+		},
+		{
+			name: "insert_default_values_with_columns",
+			b:    valid.WhenNotMatched(MergeInsert("a").DefaultValues()),
+			want: "merge INSERT DEFAULT VALUES cannot have columns or values",
+		},
+		{
+			name: "zero_action",
+			b:    valid.WhenMatched(MergeAction{}),
+			want: "merge actions must be created with MergeUpdate, MergeInsert, MergeDelete, or MergeDoNothing",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestMergeBuilderMustSQL(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("TestMergeBuilderMustSQL should have panicked!")
+		}
+	}()
+	Merge("").MustSQL()
+}
+
+func ExampleMerge() {
+	sql, args, _ := Merge("inventory i").
+		UsingSelect(Select("sku", "qty").From("deliveries").Where("day = ?", "2025-04-10"), "d").
+		On("d.sku = i.sku").
+		WhenMatched(MergeUpdate().Set("qty", Expr("i.qty + d.qty"))).
+		WhenNotMatched(MergeInsert("sku", "qty").Values(Expr("d.sku"), Expr("d.qty"))).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// MERGE INTO inventory i USING (SELECT sku, qty FROM deliveries WHERE day = $1) AS d ON d.sku = i.sku WHEN MATCHED THEN UPDATE SET qty = i.qty + d.qty WHEN NOT MATCHED THEN INSERT (sku, qty) VALUES (d.sku, d.qty)
+	// [2025-04-10]
+}
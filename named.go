@@ -0,0 +1,189 @@
+package pgq
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Named holds named arguments, referenced in SQL as @name.
+//
+// Named arguments are used when Named is the only argument passed to Expr,
+// or to the string predicates of methods such as Where, Having, Column,
+// JoinClause, Prefix, and Suffix. A name used more than once is bound to
+// the same placeholder, and SQLizer values are rendered in place of the name.
+//
+// Named and positional placeholders cannot be mixed, and SQL returns an error
+// if a name is missing from Named or if a value is never used. @@ and @ after
+// an operator or identifier character, such as in a <@ b, are not names, and
+// neither is @ inside string literals, quoted identifiers, or comments.
+//
+// Ex:
+//
+//	Expr("created_at > @since AND (owner = @owner OR reviewer = @owner)", Named{"since": t, "owner": id})
+type Named map[string]any
+
+// backRefMarker delimits a reference to a previous placeholder in the SQL
+// returned by nestedSQL. It can't be written in a valid SQL string, so it can't
+// be confused with the input.
+//
+// The number between the markers is the position of the referenced placeholder
+// counting back from the last placeholder, which is 1. This keeps references valid
+// when the SQL is embedded in another query.
+const backRefMarker = '\x00'
+
+// namedSQL replaces the @name references in sql with placeholders if args is a
+// single Named value. Otherwise, it returns sql and args unchanged.
+func namedSQL(sql string, args []any) (string, []any, error) {
+	if len(args) != 1 {
+		return sql, args, nil
+	}
+	named, ok := args[0].(Named)
+	if !ok {
+		return sql, args, nil
+	}
+	return named.expand(sql)
+}
+
+func (n Named) expand(sql string) (string, []any, error) {
+	var (
+		buf       = &bytes.Buffer{}
+		args      []any
+		count     int
+		positions = map[string]int{}
+		used      = map[string]bool{}
+		l         = newLexer(sql)
+	)
+	l.named = true
+	for kind, text := l.next(); kind != tokenEOF; kind, text = l.next() {
+		switch kind {
+		case tokenPlaceholder:
+			return "", nil, fmt.Errorf("positional placeholders cannot be used with named parameters in %q", sql)
+		case tokenName:
+			name := text[1:]
+			v, ok := n[name]
+			if !ok {
+				return "", nil, fmt.Errorf("named parameter %q is missing", name)
+			}
+			used[name] = true
+
+			if s, ok := v.(SQLizer); ok {
+				vsql, vargs, err := nestedSQL(s)
+				if err != nil {
+					return "", nil, err
+				}
+				buf.WriteString(vsql)
+				args = append(args, vargs...)
+				count += countPlaceholders(vsql)
+				continue
+			}
+			if p, ok := positions[name]; ok {
+				buf.WriteByte(backRefMarker)
+				buf.WriteString(strconv.Itoa(count - p + 1))
+				buf.WriteByte(backRefMarker)
+				continue
+			}
+			buf.WriteByte('?')
+			args = append(args, v)
+			count++
+			positions[name] = count
+		default:
+			buf.WriteString(text)
+		}
+	}
+
+	if len(used) != len(n) {
+		names := make([]string, 0, len(n))
+		for name := range n {
+			if !used[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return "", nil, fmt.Errorf("named parameter %q is not used", names[0])
+	}
+	return buf.String(), args, nil
+}
+
+// resolvedSQL returns the SQL of s with its back-references resolved, for the
+// SQL method of SQLizers that aren't statements, which don't finalize
+// placeholders, but must not expose back-references to the caller.
+func resolvedSQL(s rawSQLizer) (string, []any, error) {
+	sql, args, err := s.unfinalizedSQL()
+	if err != nil {
+		return "", nil, err
+	}
+	return resolveBackRefs(sql, args)
+}
+
+// resolveBackRefs replaces the back-references in sql with "?" placeholders,
+// repeating the referenced args.
+func resolveBackRefs(sql string, args []any) (string, []any, error) {
+	if strings.IndexByte(sql, backRefMarker) == -1 {
+		return sql, args, nil
+	}
+
+	var (
+		buf      = &bytes.Buffer{}
+		resolved = make([]any, 0, len(args))
+		i        int
+		l        = newLexer(sql)
+	)
+	for {
+		kind, text := l.next()
+		switch kind {
+		case tokenEOF:
+			return buf.String(), append(resolved, args[i:]...), nil
+		case tokenPlaceholder:
+			if i >= len(args) {
+				return "", nil, fmt.Errorf("not enough args for placeholders in %q", sql)
+			}
+			resolved = append(resolved, args[i])
+			i++
+			buf.WriteString(text)
+		case tokenBackRef:
+			k, err := readBackRef(text)
+			if err != nil {
+				return "", nil, err
+			}
+			if k > i {
+				return "", nil, fmt.Errorf("placeholder reference to %d placeholders back, but only %d found", k, i)
+			}
+			resolved = append(resolved, args[i-k])
+			buf.WriteString("?")
+		default:
+			buf.WriteString(text)
+		}
+	}
+}
+
+// readBackRef returns the position of the placeholder referenced by ref,
+// a tokenBackRef.
+func readBackRef(ref string) (int, error) {
+	if len(ref) < 3 || ref[len(ref)-1] != backRefMarker {
+		return 0, fmt.Errorf("invalid placeholder reference %q", ref)
+	}
+	k, err := strconv.Atoi(ref[1 : len(ref)-1])
+	if err != nil || k < 1 {
+		return 0, fmt.Errorf("invalid placeholder reference %q", ref)
+	}
+	return k, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || '0' <= c && c <= '9'
+}
+
+func isOperatorChar(c byte) bool {
+	switch c {
+	case '+', '-', '*', '/', '<', '>', '=', '~', '!', '@', '#', '%', '^', '&', '|', '`', '?':
+		return true
+	}
+	return false
+}
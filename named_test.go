@@ -0,0 +1,198 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "expr",
+			b:        Expr("created_at > @since AND owner = @owner", Named{"since": "2024-01-01", "owner": 7}),
+			wantSQL:  "created_at > ? AND owner = ?",
+			wantArgs: []any{"2024-01-01", 7},
+		},
+		{
+			name:     "expr_repeated",
+			b:        Expr("owner = @id OR reviewer = @id OR @x = @id", Named{"id": 7, "x": 1}),
+			wantSQL:  "owner = ? OR reviewer = ? OR ? = ?",
+			wantArgs: []any{7, 7, 1, 7},
+		},
+		{
+			name:     "repeated",
+			b:        Select("*").From("t").Where(Expr("owner = @id OR reviewer = @id OR @x = @id", Named{"id": 7, "x": 1})),
+			wantSQL:  "SELECT * FROM t WHERE owner = $1 OR reviewer = $1 OR $2 = $1",
+			wantArgs: []any{7, 1},
+		},
+		{
+			name:     "cast_and_operators",
+			b:        Select("*").From("t").Where("@a::int + @b <@ tags AND tsv @@ q AND email = 'user@example.com' AND \"@col\" = @a", Named{"a": "1", "b": 2}),
+			wantSQL:  "SELECT * FROM t WHERE $1::int + $2 <@ tags AND tsv @@ q AND email = 'user@example.com' AND \"@col\" = $1",
+			wantArgs: []any{"1", 2},
+		},
+		{
+			name:     "escaped_question_mark",
+			b:        Select("*").From("t").Where("data ?? 'k' AND id = @id", Named{"id": 1}),
+			wantSQL:  "SELECT * FROM t WHERE data ? 'k' AND id = $1",
+			wantArgs: []any{1},
+		},
+		{
+			name:     "sqlizer_value",
+			b:        Select("*").From("u").Where(Expr("id IN (@ids) AND k = @k AND id <> @k", Named{"ids": Select("id").From("t").Where("v = ?", 1), "k": 2})),
+			wantSQL:  "SELECT * FROM u WHERE id IN (SELECT id FROM t WHERE v = $1) AND k = $2 AND id <> $2",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "select",
+			b: Select("id").
+				Prefix("WITH prefix AS @tag", Named{"tag": "t"}).
+				Column("coalesce(name, @default) AS name", Named{"default": "n/a"}).
+				From("users u").
+				JoinClause("JOIN teams t ON t.id = u.team_id AND t.kind = @kind", Named{"kind": "dev"}).
+				Where("u.id > ?", 10).
+				Where("u.created_at BETWEEN @from AND @to OR u.updated_at BETWEEN @from AND @to", Named{"from": 1, "to": 2}).
+				GroupBy("id").
+				Having("count(*) > @min", Named{"min": 3}).
+				Suffix("FETCH FIRST @n ROWS ONLY", Named{"n": "s"}),
+			wantSQL: "WITH prefix AS $1 SELECT id, coalesce(name, $2) AS name FROM users u " +
+				"JOIN teams t ON t.id = u.team_id AND t.kind = $3 " +
+				"WHERE u.id > $4 AND u.created_at BETWEEN $5 AND $6 OR u.updated_at BETWEEN $5 AND $6 " +
+				"GROUP BY id HAVING count(*) > $7 FETCH FIRST $8 ROWS ONLY",
+			wantArgs: []any{"t", "n/a", "dev", 10, 1, 2, 3, "s"},
+		},
+		{
+			name: "nested",
+			b: Select("*").
+				From("t").
+				Where("a = ?", 0).
+				Where(Expr("b = @b OR c = @b", Named{"b": Expr("@x + @x", Named{"x": 1})})),
+			wantSQL:  "SELECT * FROM t WHERE a = $1 AND b = $2 + $2 OR c = $3 + $3",
+			wantArgs: []any{0, 1, 1},
+		},
+		{
+			name:     "and_standalone",
+			b:        And{Expr("a = @x OR b = @x", Named{"x": 1}), Eq{"c": 2}},
+			wantSQL:  "(a = ? OR b = ? AND c = ?)",
+			wantArgs: []any{1, 1, 2},
+		},
+		{
+			name:     "or_standalone",
+			b:        Or{Expr("a = @x", Named{"x": 1}), Expr("b = @x", Named{"x": 2}), Expr("c = @y + @y", Named{"y": 3})},
+			wantSQL:  "(a = ? OR b = ? OR c = ? + ?)",
+			wantArgs: []any{1, 2, 3, 3},
+		},
+		{
+			name:     "alias_standalone",
+			b:        Alias{Expr: Expr("coalesce(@v, @v)", Named{"v": "x"}), As: "v"},
+			wantSQL:  "(coalesce(?, ?)) AS v",
+			wantArgs: []any{"x", "x"},
+		},
+		{
+			name:     "case_standalone",
+			b:        Case().When(Expr("a = @x OR b = @x", Named{"x": 1}), "1").Else(Expr("@y || @y", Named{"y": "z"})),
+			wantSQL:  "CASE WHEN a = ? OR b = ? THEN 1 ELSE ? || ? END",
+			wantArgs: []any{1, 1, "z", "z"},
+		},
+		{
+			name:     "and_nested",
+			b:        Select("*").From("t").Where(And{Expr("a = @x OR b = @x", Named{"x": 1}), Eq{"c": 2}}),
+			wantSQL:  "SELECT * FROM t WHERE (a = $1 OR b = $1 AND c = $2)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "delete_update",
+			b: Update("t").
+				Set("x", 1).
+				Where("a = @v OR b = @v", Named{"v": 2}).
+				Where("c = ?", 3),
+			wantSQL:  "UPDATE t SET x = $1 WHERE a = $2 OR b = $2 AND c = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestNamedErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "missing",
+			b:    Expr("a = @a AND b = @b", Named{"a": 1}),
+			want: `named parameter "b" is missing`,
+		},
+		{
+			name: "unused",
+			b:    Select("*").From("t").Where("a = @a", Named{"a": 1, "z": 2, "y": 3}),
+			want: `named parameter "y" is not used`,
+		},
+		{
+			name: "positional",
+			b:    Select("*").From("t").Where("a = @a AND b = ?", Named{"a": 1}),
+			want: `positional placeholders cannot be used with named parameters in "a = @a AND b = ?"`,
+		},
+		{
+			name: "sqlizer_error",
+			b:    Expr("a IN (@q)", Named{"q": Select()}),
+			want: "select statements must have at least one result column",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestNamedDebug(t *testing.T) {
+	t.Parallel()
+	b := Select("*").From("t").Where("a = ?", 0).Where("b = @b OR c = @b", Named{"b": 1})
+	want := "SELECT * FROM t WHERE a = '0' AND b = '1' OR c = '1'"
+	if got := Debug(b); got != want {
+		t.Errorf("expected %q, got %q instead", want, got)
+	}
+}
+
+func ExampleNamed() {
+	sql, args, _ := Select("*").
+		From("tasks").
+		Where("created_at > @since AND (owner = @user OR reviewer = @user)", Named{"since": "2024-01-01", "user": 42}).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT * FROM tasks WHERE created_at > $1 AND (owner = $2 OR reviewer = $2)
+	// [2024-01-01 42]
+}
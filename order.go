@@ -0,0 +1,195 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Order is an ORDER BY expression with its sort direction and NULLS ordering.
+//
+// Ex:
+//
+//	Select("*").From("users").Sort(Desc("created_at").NullsLast(), Asc("id"))
+//	// SELECT * FROM users ORDER BY created_at DESC NULLS LAST, id ASC
+type Order struct {
+	expr  SQLizer
+	dir   string
+	using string
+	nulls string
+}
+
+// Asc returns the ascending order of expr, which can be a string or a SQLizer.
+func Asc(expr any) Order {
+	return Order{expr: newPart(expr), dir: "ASC"}
+}
+
+// Desc returns the descending order of expr, which can be a string or a SQLizer.
+func Desc(expr any) Order {
+	return Order{expr: newPart(expr), dir: "DESC"}
+}
+
+// Using orders by the sort operator op, such as < or >, instead of ASC or DESC.
+func (o Order) Using(op string) Order {
+	o.dir = ""
+	o.using = op
+	return o
+}
+
+// NullsFirst sorts null values before non-null values.
+func (o Order) NullsFirst() Order {
+	o.nulls = "FIRST"
+	return o
+}
+
+// NullsLast sorts null values after non-null values.
+func (o Order) NullsLast() Order {
+	o.nulls = "LAST"
+	return o
+}
+
+// SQL returns the ORDER BY expression.
+func (o Order) SQL() (string, []any, error) {
+	return resolvedSQL(o)
+}
+
+func (o Order) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if o.expr == nil {
+		err = errors.New("orders must be created with Asc or Desc")
+		return
+	}
+	sql := &bytes.Buffer{}
+	args, err = appendSQL([]SQLizer{o.expr}, sql, "", args)
+	if err != nil {
+		return
+	}
+	if sql.Len() == 0 {
+		err = errors.New("orders must have an expression")
+		return
+	}
+
+	if o.using != "" {
+		if !isOperator(o.using) {
+			err = fmt.Errorf("invalid USING operator %q", o.using)
+			return
+		}
+		sql.WriteString(" USING ")
+		sql.WriteString(o.using)
+	} else if o.dir != "" {
+		sql.WriteString(" ")
+		sql.WriteString(o.dir)
+	}
+
+	if o.nulls != "" {
+		sql.WriteString(" NULLS ")
+		sql.WriteString(o.nulls)
+	}
+	sqlStr = sql.String()
+	return
+}
+
+// isOperator reports whether op only has operator characters, so it
+// cannot be used to inject SQL.
+func isOperator(op string) bool {
+	if op == "" || strings.Contains(op, "?") || strings.Contains(op, "--") || strings.Contains(op, "/*") {
+		return false
+	}
+	for i := 0; i < len(op); i++ {
+		if !isOperatorChar(op[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnknownSortFieldError is returned by SortSpec.Parse for fields that aren't
+// in the SortSpec.
+type UnknownSortFieldError struct {
+	Field string
+}
+
+func (e *UnknownSortFieldError) Error() string {
+	return fmt.Sprintf("unknown sort field %q", e.Field)
+}
+
+// SortSpec maps the field names users can sort by, such as in an API's
+// ?sort= parameter, to the column identifiers they are sorted by.
+//
+// Only the fields in the SortSpec are allowed, and the identifiers are quoted
+// with QuoteIdent, so user input never reaches the query as SQL.
+//
+// Ex:
+//
+//	spec := SortSpec{"name": "u.name", "created": "u.created_at"}
+//	orders, err := spec.Parse(r.URL.Query().Get("sort")) // "-created,name"
+//	...
+//	Select("*").From("users u").Sort(orders...)
+//	// SELECT * FROM users u ORDER BY "u"."created_at" DESC, "u"."name" ASC
+type SortSpec map[string]string
+
+// Parse parses a comma-separated list of sort fields into orders.
+// Fields prefixed with "-" are sorted in descending order, and the others,
+// optionally prefixed with "+", in ascending order.
+//
+// Parse returns an *UnknownSortFieldError for fields that aren't in the SortSpec.
+func (s SortSpec) Parse(sort string) ([]Order, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+	var (
+		fields = strings.Split(sort, ",")
+		orders = make([]Order, 0, len(fields))
+		seen   = make(map[string]bool, len(fields))
+	)
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		if desc || strings.HasPrefix(field, "+") {
+			field = field[1:]
+		}
+		if field == "" {
+			return nil, errors.New("sort fields cannot be empty")
+		}
+
+		ident, ok := s[field]
+		if !ok {
+			return nil, &UnknownSortFieldError{Field: field}
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("sort field %q is repeated", field)
+		}
+		seen[field] = true
+
+		if desc {
+			orders = append(orders, Desc(Ident(ident)))
+		} else {
+			orders = append(orders, Asc(Ident(ident)))
+		}
+	}
+	return orders, nil
+}
+
+// Sort adds orders to the ORDER BY clause of the query.
+func (b SelectBuilder) Sort(orders ...Order) SelectBuilder {
+	for _, o := range orders {
+		b = b.OrderByClause(o)
+	}
+	return b
+}
+
+// Sort adds orders to the ORDER BY clause of the query.
+func (b UpdateBuilder) Sort(orders ...Order) UpdateBuilder {
+	for _, o := range orders {
+		b = b.OrderByClause(o)
+	}
+	return b
+}
+
+// Sort adds orders to the ORDER BY clause of the query.
+func (b DeleteBuilder) Sort(orders ...Order) DeleteBuilder {
+	for _, o := range orders {
+		b = b.OrderByClause(o)
+	}
+	return b
+}
@@ -0,0 +1,240 @@
+package pgq
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "asc",
+			b:       Asc("id"),
+			wantSQL: "id ASC",
+		},
+		{
+			name:    "desc_nulls_last",
+			b:       Desc("created_at").NullsLast(),
+			wantSQL: "created_at DESC NULLS LAST",
+		},
+		{
+			name:    "nulls_first",
+			b:       Asc(Ident("Name")).NullsFirst(),
+			wantSQL: `"Name" ASC NULLS FIRST`,
+		},
+		{
+			name:    "using",
+			b:       Asc("price").Using(">").NullsLast(),
+			wantSQL: "price USING > NULLS LAST",
+		},
+		{
+			name:     "expr",
+			b:        Desc(Expr("similarity(name, ?)", "foo")),
+			wantSQL:  "similarity(name, ?) DESC",
+			wantArgs: []any{"foo"},
+		},
+		{
+			name: "select",
+			b: Select("*").From("users").Where("active = ?", true).
+				Sort(Desc(Expr("score * ?", 2)).NullsLast(), Asc("id")).
+				Limit(10),
+			wantSQL:  "SELECT * FROM users WHERE active = $1 ORDER BY score * $2 DESC NULLS LAST, id ASC LIMIT 10",
+			wantArgs: []any{true, 2},
+		},
+		{
+			name:     "select_distinct_on",
+			b:        Select("*").DistinctOn("user_id").From("events").Sort(Asc("user_id").NullsFirst(), Desc("created_at")),
+			wantSQL:  "SELECT DISTINCT ON (user_id) * FROM events ORDER BY user_id ASC NULLS FIRST, created_at DESC",
+			wantArgs: nil,
+		},
+		{
+			name:     "update",
+			b:        Update("jobs").Set("status", "done").Where("id > ?", 1).Sort(Asc("id")).OrderByClause("priority <-> ?", 5),
+			wantSQL:  "UPDATE jobs SET status = $1 WHERE id > $2 ORDER BY id ASC, priority <-> $3",
+			wantArgs: []any{"done", 1, 5},
+		},
+		{
+			name:     "delete",
+			b:        Delete("jobs").Where("status = ?", "done").OrderBy("a").Sort(Desc("created_at").NullsFirst()),
+			wantSQL:  "DELETE FROM jobs WHERE status = $1 ORDER BY a, created_at DESC NULLS FIRST",
+			wantArgs: []any{"done"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestOrderErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "zero",
+			b:    Order{},
+			want: "orders must be created with Asc or Desc",
+		},
+		{
+			name: "empty",
+			b:    Asc(""),
+			want: "orders must have an expression",
+		},
+		{
+			name: "using",
+			b:    Asc("id").Using("; DROP TABLE users"),
+			want: `invalid USING operator "; DROP TABLE users"`,
+		},
+		{
+			name: "using_comment",
+			b:    Asc("id").Using("<--"),
+			want: `invalid USING operator "<--"`,
+		},
+		{
+			name: "using_placeholder",
+			b:    Asc("id").Using("?"),
+			want: `invalid USING operator "?"`,
+		},
+		{
+			name: "select",
+			b:    Select("*").From("t").Sort(Asc(Ident(""))),
+			want: "identifiers cannot be empty",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestSortSpec(t *testing.T) {
+	t.Parallel()
+	spec := SortSpec{
+		"name":    "u.name",
+		"created": "u.created_at",
+		"Weird":   `we"ird`,
+	}
+	testCases := []struct {
+		name string
+		sort string
+		want string
+	}{
+		{"empty", " ", "SELECT * FROM users u"},
+		{"asc", "name", `SELECT * FROM users u ORDER BY "u"."name" ASC`},
+		{"plus", "+name", `SELECT * FROM users u ORDER BY "u"."name" ASC`},
+		{"many", " -created , name", `SELECT * FROM users u ORDER BY "u"."created_at" DESC, "u"."name" ASC`},
+		{"quoted", "-Weird", `SELECT * FROM users u ORDER BY "we""ird" DESC`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			orders, err := spec.Parse(tc.sort)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sql, _, err := Select("*").From("users u").Sort(orders...).SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.want {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.want, sql)
+			}
+		})
+	}
+}
+
+func TestSortSpecErr(t *testing.T) {
+	t.Parallel()
+	spec := SortSpec{"name": "name", "id": "id"}
+	testCases := []struct {
+		name string
+		sort string
+		want string
+	}{
+		{"unknown", "name,password", `unknown sort field "password"`},
+		{"injection", "name; DROP TABLE users", `unknown sort field "name; DROP TABLE users"`},
+		{"case", "Name", `unknown sort field "Name"`},
+		{"empty_field", "name,,id", "sort fields cannot be empty"},
+		{"only_sign", "-", "sort fields cannot be empty"},
+		{"repeated", "name,-name", `sort field "name" is repeated`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			orders, err := spec.Parse(tc.sort)
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+			if orders != nil {
+				t.Errorf("expected no orders, got %v instead", orders)
+			}
+		})
+	}
+
+	_, err := spec.Parse("-email")
+	var unknown *UnknownSortFieldError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownSortFieldError, got %T instead", err)
+	}
+	if unknown.Field != "email" {
+		t.Errorf("expected field to be %q, got %q instead", "email", unknown.Field)
+	}
+}
+
+func ExampleSortSpec_Parse() {
+	spec := SortSpec{
+		"name":    "u.name",
+		"created": "u.created_at",
+	}
+	orders, err := spec.Parse("-created,name")
+	if err != nil {
+		panic(err)
+	}
+	sql, args, _ := Select("u.id", "u.name").
+		From("users u").
+		Where("u.active = ?", true).
+		Sort(orders...).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+
+	_, err = spec.Parse("password")
+	var unknown *UnknownSortFieldError
+	fmt.Println(errors.As(err, &unknown), unknown.Field)
+	// Output:
+	// SELECT u.id, u.name FROM users u WHERE u.active = $1 ORDER BY "u"."created_at" DESC, "u"."name" ASC
+	// [true]
+	// true password
+}
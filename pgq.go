@@ -7,7 +7,6 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"strings"
 )
 
 // SQLizer is the interface that wraps the SQL method.
@@ -20,6 +19,10 @@ type SQLizer interface {
 
 // rawSQLizer is expected to do what SQLizer does, but without finalizing placeholders.
 // This is useful for nested queries.
+//
+// Statement builders finalize placeholders in SQL, so anything that embeds
+// another SQLizer must render it with nestedSQL to let only the outermost
+// builder assign the $N positions.
 type rawSQLizer interface {
 	unfinalizedSQL() (string, []any, error)
 }
@@ -34,46 +37,48 @@ type rawSQLizer interface {
 // not try very hard to ensure it. Additionally, executing the output of this
 // function with any untrusted user input is certainly insecure.
 func Debug(s SQLizer) string {
-	sql, args, err := s.SQL()
+	sql, args, err := nestedSQL(s)
 	if err != nil {
 		return fmt.Sprintf("[SQL error: %s]", err)
 	}
 
 	buf := &bytes.Buffer{}
 	i := 0
+	rest := 0 // position after the last placeholder or escape, for error messages
+	l := newLexer(sql)
 	for {
-		p := strings.Index(sql, "?")
-		if p == -1 {
-			break
-		}
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
+		kind, text := l.next()
+		switch kind {
+		case tokenEOF:
+			if i < len(args) {
+				return fmt.Sprintf(
+					"[DebugSQLizer error: not enough placeholders in %#v for %d args]",
+					sql[rest:], len(args))
 			}
-			sql = sql[p+2:]
-		} else {
+			return buf.String()
+		case tokenPlaceholder:
 			if i+1 > len(args) {
 				return fmt.Sprintf(
 					"[DebugSQLizer error: too many placeholders in %#v for %d args]",
-					sql, len(args))
+					sql[rest:], len(args))
 			}
-			buf.WriteString(sql[:p])
 			fmt.Fprintf(buf, "'%v'", args[i])
-			// advance our sql string "cursor" beyond the arg we placed
-			sql = sql[p+1:]
 			i++
+			rest = l.pos
+		case tokenBackRef:
+			k, err := readBackRef(text)
+			if err != nil || k > i {
+				return fmt.Sprintf("[DebugSQLizer error: invalid placeholder reference in %#v]", sql)
+			}
+			fmt.Fprintf(buf, "'%v'", args[i-k])
+			rest = l.pos
+		case tokenEscape:
+			buf.WriteString("?")
+			rest = l.pos
+		default:
+			buf.WriteString(text)
 		}
 	}
-	if i < len(args) {
-		return fmt.Sprintf(
-			"[DebugSQLizer error: not enough placeholders in %#v for %d args]",
-			sql, len(args))
-	}
-	// "append" any remaning sql that won't need interpolating
-	buf.WriteString(sql)
-	return buf.String()
 }
 
 type part struct {
@@ -85,21 +90,33 @@ func newPart(pred any, args ...any) SQLizer {
 	return &part{pred, args}
 }
 
-func (p part) SQL() (sql string, args []any, err error) {
+func (p part) SQL() (string, []any, error) {
+	return resolvedSQL(p)
+}
+
+func (p part) unfinalizedSQL() (sql string, args []any, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
 		// no-op
 	case SQLizer:
 		sql, args, err = nestedSQL(pred)
 	case string:
-		sql = pred
-		args = p.args
+		sql, args, err = namedSQL(pred, p.args)
 	default:
 		err = fmt.Errorf("expected string or SQLizer, not %T", pred)
 	}
 	return
 }
 
+// newValuePart returns v if it is a SQLizer, or a placeholder bound to v.
+func newValuePart(v any) SQLizer {
+	if s, ok := v.(SQLizer); ok {
+		return s
+	}
+	return expr{sql: "?", args: []any{v}}
+}
+
+// nestedSQL returns the SQL of s with "?" placeholders, without finalizing it.
 func nestedSQL(s SQLizer) (string, []any, error) {
 	if raw, ok := s.(rawSQLizer); ok {
 		return raw.unfinalizedSQL()
@@ -1,6 +1,7 @@
 package pgq
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -42,3 +43,130 @@ func TestDebugSQLizerErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestNestedPlaceholderNumbering(t *testing.T) {
+	t.Parallel()
+	sub := Select("max(v)").From("u").Where("k = ?", 1)
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "expr_arg",
+			b:        Select("*").From("t").Where("a = ?", 0).Where(Expr("b = (?)", sub)).Where("c = ?", 2),
+			wantSQL:  "SELECT * FROM t WHERE a = $1 AND b = (SELECT max(v) FROM u WHERE k = $2) AND c = $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "expr_arg_nested_expr",
+			b:        Select("*").Where(Expr("a = ? AND ?", 0, Expr("b IN (?)", sub))).Where("c = ?", 2),
+			wantSQL:  "SELECT * WHERE a = $1 AND b IN (SELECT max(v) FROM u WHERE k = $2) AND c = $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "alias",
+			b:        Select("a").Column("? AS x", 0).Column(Alias{Expr: sub, As: "m"}).Where("c = ?", 2),
+			wantSQL:  "SELECT a, $1 AS x, (SELECT max(v) FROM u WHERE k = $2) AS m WHERE c = $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "case",
+			b:        Select().Column(Case().When(Expr("a = ?", 0), Expr("(?)", sub)).Else(Expr("?", 2))).From("t"),
+			wantSQL:  "SELECT CASE WHEN a = $1 THEN (SELECT max(v) FROM u WHERE k = $2) ELSE $3 END FROM t",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "select_from_select",
+			b:        Select("*").Column("? AS x", 0).FromSelect(sub, "s").Where("c = ?", 2),
+			wantSQL:  "SELECT *, $1 AS x FROM (SELECT max(v) FROM u WHERE k = $2) AS s WHERE c = $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "update_from_select",
+			b:        Update("t").Set("a", 0).FromSelect(sub, "s").Where("c = ?", 2),
+			wantSQL:  "UPDATE t SET a = $1 FROM (SELECT max(v) FROM u WHERE k = $2) AS s WHERE c = $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "update_set_select",
+			b:        Update("t").Set("x", sub).Where("id = ?", 2),
+			wantSQL:  "UPDATE t SET x = (SELECT max(v) FROM u WHERE k = $1) WHERE id = $2",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "update_returning_select",
+			b:        Update("t").Set("a", 0).Where("c = ?", 2).ReturningSelect(sub, "m"),
+			wantSQL:  "UPDATE t SET a = $1 WHERE c = $2 RETURNING (SELECT max(v) FROM u WHERE k = $3) AS m",
+			wantArgs: []any{0, 2, 1},
+		},
+		{
+			name:     "delete_using_select",
+			b:        Delete("t").Prefix("WITH prefix AS ?", 0).UsingSelect(sub, "s").Where("c = ?", 2),
+			wantSQL:  "WITH prefix AS $1 DELETE FROM t USING (SELECT max(v) FROM u WHERE k = $2) AS s WHERE c = $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "delete_returning_select",
+			b:        Delete("t").Where("c = ?", 2).ReturningSelect(sub, "m"),
+			wantSQL:  "DELETE FROM t WHERE c = $1 RETURNING (SELECT max(v) FROM u WHERE k = $2) AS m",
+			wantArgs: []any{2, 1},
+		},
+		{
+			name:     "insert_select",
+			b:        Insert("t").Prefix("WITH prefix AS ?", 0).Columns("m").Select(sub).Suffix("RETURNING ?", 2),
+			wantSQL:  "WITH prefix AS $1 INSERT INTO t (m) SELECT max(v) FROM u WHERE k = $2 RETURNING $3",
+			wantArgs: []any{0, 1, 2},
+		},
+		{
+			name:     "insert_values_select",
+			b:        Insert("t").Columns("a", "m").Values(0, Expr("(?)", sub)).Values(2, 3),
+			wantSQL:  "INSERT INTO t (a,m) VALUES ($1,(SELECT max(v) FROM u WHERE k = $2)),($3,$4)",
+			wantArgs: []any{0, 1, 2, 3},
+		},
+		{
+			name:     "insert_returning_select",
+			b:        Insert("t").Values(0).ReturningSelect(sub, "m"),
+			wantSQL:  "INSERT INTO t VALUES ($1) RETURNING (SELECT max(v) FROM u WHERE k = $2) AS m",
+			wantArgs: []any{0, 1},
+		},
+		{
+			name: "deeply_nested",
+			b: Update("t").
+				Set("x", Select("max(v)").FromSelect(sub.Where(Expr("j = (?)", Select("1").Where("z = ?", 3))), "s")).
+				Where("id = ?", 2),
+			wantSQL: "UPDATE t SET x = (SELECT max(v) FROM " +
+				"(SELECT max(v) FROM u WHERE k = $1 AND j = (SELECT 1 WHERE z = $2)) AS s) " +
+				"WHERE id = $3",
+			wantArgs: []any{1, 3, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestDebugNested(t *testing.T) {
+	t.Parallel()
+	b := Update("t").Set("x", Select("max(v)").From("u").Where("k = ?", 1)).Where("id = ?", 2)
+	want := "UPDATE t SET x = (SELECT max(v) FROM u WHERE k = '1') WHERE id = '2'"
+	if got := Debug(b); got != want {
+		t.Errorf("expected %q, got %q instead", want, got)
+	}
+}
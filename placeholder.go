@@ -6,15 +6,6 @@ import (
 	"strings"
 )
 
-// placeholder takes a SQL statement and replaces each question mark
-// placeholder with a (possibly different) SQL placeholder.
-type placeholder func(sql string) (string, error)
-
-// questionPlaceholder just leaves question marks ("?") as placeholders.
-func questionPlaceholder(sql string) (string, error) {
-	return sql, nil
-}
-
 // Placeholders returns a string with count ? placeholders joined with commas.
 func Placeholders(count int) string {
 	if count < 1 {
@@ -26,30 +17,47 @@ func Placeholders(count int) string {
 
 // dollarPlaceholder replaces question marks ("?") placeholders with
 // dollar-prefixed positional placeholders (e.g. $1, $2, $3).
+//
+// See lexer for what is considered a placeholder.
 func dollarPlaceholder(sql string) (string, error) {
 	buf := &bytes.Buffer{}
 	i := 0
+	l := newLexer(sql)
 	for {
-		p := strings.Index(sql, "?")
-		if p == -1 {
-			break
-		}
-
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
-		} else {
+		kind, text := l.next()
+		switch kind {
+		case tokenEOF:
+			return buf.String(), nil
+		case tokenPlaceholder:
 			i++
-			buf.WriteString(sql[:p])
 			fmt.Fprintf(buf, "$%d", i)
-			sql = sql[p+1:]
+		case tokenBackRef:
+			k, err := readBackRef(text)
+			if err != nil {
+				return "", err
+			}
+			if k > i {
+				return "", fmt.Errorf("placeholder reference to %d placeholders back, but only %d found", k, i)
+			}
+			fmt.Fprintf(buf, "$%d", i-k+1)
+		case tokenEscape:
+			buf.WriteString("?")
+		default:
+			buf.WriteString(text)
 		}
 	}
+}
 
-	buf.WriteString(sql)
-	return buf.String(), nil
+// countPlaceholders returns the number of "?" placeholders in sql.
+func countPlaceholders(sql string) int {
+	count := 0
+	l := newLexer(sql)
+	for {
+		switch kind, _ := l.next(); kind {
+		case tokenEOF:
+			return count
+		case tokenPlaceholder:
+			count++
+		}
+	}
 }
@@ -17,6 +17,62 @@ func TestDollar(t *testing.T) {
 	}
 }
 
+func TestDollarContext(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"string", "SELECT '?', 'it''s ?', ?", "SELECT '?', 'it''s ?', $1"},
+		{"escape_string", `SELECT E'\'?', e'\\', ?`, `SELECT E'\'?', e'\\', $1`},
+		{"quoted_identifier", `SELECT "a?""b" FROM t WHERE x = ?`, `SELECT "a?""b" FROM t WHERE x = $1`},
+		{"dollar_quoted", "DO $$ SELECT ? $$; DO $fn$ $$ ? $fn$; SELECT ?", "DO $$ SELECT ? $$; DO $fn$ $$ ? $fn$; SELECT $1"},
+		{"line_comment", "SELECT ? -- what?\n, ?", "SELECT $1 -- what?\n, $2"},
+		{"block_comment", "SELECT /* a /* nested? */ b? */ ?", "SELECT /* a /* nested? */ b? */ $1"},
+		{"jsonb_operators", "SELECT * FROM t WHERE data ? 'a' AND data ?| ? AND (data->'x') ?& ?", "SELECT * FROM t WHERE data ? 'a' AND data ?| $1 AND (data->'x') ?& $2"},
+		{"jsonb_operator_after_placeholder", "SELECT ?::jsonb ? ?", "SELECT $1::jsonb ? $2"},
+		{"jsonb_operator_after_literal", "SELECT '{}'::jsonb ? 'a', tags[1] ? 'b', 1 ? 2", "SELECT '{}'::jsonb ? 'a', tags[1] ? 'b', 1 ? 2"},
+		{"keywords", "SELECT ? WHERE a BETWEEN ? AND ? OR b IN (?) LIMIT ? OFFSET ?", "SELECT $1 WHERE a BETWEEN $2 AND $3 OR b IN ($4) LIMIT $5 OFFSET $6"},
+		{"case", "CASE WHEN ? THEN ? ELSE ? END", "CASE WHEN $1 THEN $2 ELSE $3 END"},
+		{"positional", "SELECT $1 ? 'a', $2", "SELECT $1 ? 'a', $2"},
+		{"escaped", "SELECT data ?? 'a', '??', ?", "SELECT data ? 'a', '?', $1"},
+		{"unterminated", "SELECT 'abc ?", "SELECT 'abc ?"},
+		{"jsonb_path_exists", "x = ? AND y @? ?", "x = $1 AND y @? $2"},
+		{"jsonb_path_exists_escaped", "y @?? ? AND z @@ ?", "y @? $1 AND z @@ $2"},
+		{"qualified_keyword", "t.range ? 'k' AND t.first ?| ?", "t.range ? 'k' AND t.first ?| $1"},
+		{"variadic", "SELECT concat_ws(',', VARIADIC ?)", "SELECT concat_ws(',', VARIADIC $1)"},
+		{"operator", "x OPERATOR(pg_catalog.=) ? AND y operator(pg_catalog.?|) ?", "x OPERATOR(pg_catalog.=) $1 AND y operator(pg_catalog.?|) $2"},
+	}
+	for _, tc := range testCases {
+		s, err := dollarPlaceholder(tc.sql)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+		if s != tc.want {
+			t.Errorf("%s: expected %q, got %q instead", tc.name, tc.want, s)
+		}
+	}
+}
+
+func TestDollarBackReference(t *testing.T) {
+	t.Parallel()
+	sql := "x = ? AND y = ? AND z = \x002\x00 AND w = \x001\x00"
+	s, err := dollarPlaceholder(sql)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if want := "x = $1 AND y = $2 AND z = $1 AND w = $2"; s != want {
+		t.Errorf("expected %q, got %q instead", want, s)
+	}
+
+	for _, sql := range []string{"x = \x001\x00", "x = ? AND y = \x002\x00", "x = ? AND y = \x00", "x = ? AND y = \x00a\x00"} {
+		if _, err := dollarPlaceholder(sql); err == nil {
+			t.Errorf("expected error for %q", sql)
+		}
+	}
+}
+
 func TestPlaceholders(t *testing.T) {
 	t.Parallel()
 	got := Placeholders(2)
@@ -32,12 +88,59 @@ func TestEscapeDollar(t *testing.T) {
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	want := "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['$1'] AND enabled = $2"
+	want := "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['?'] AND enabled = $1"
 	if s != want {
 		t.Errorf("expected %q, got %q instead", want, s)
 	}
 }
 
+func FuzzDollarPlaceholder(f *testing.F) {
+	f.Add("x = ? AND y = ?", "it's ?")
+	f.Add("data ? 'a' AND y = ?", "??")
+	f.Add("SELECT $$?$$, E'\\'?'", "*/ ?")
+	f.Add("/* ? */ -- ?\n?", "$$")
+	f.Fuzz(func(t *testing.T, sql, text string) {
+		if strings.ContainsRune(sql, backRefMarker) {
+			return
+		}
+		s, err := dollarPlaceholder(sql)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", sql, err)
+		}
+		if !strings.Contains(sql, "?") && s != sql {
+			t.Errorf("expected SQL without placeholders to be unchanged, got %q for %q", s, sql)
+		}
+
+		// "?" inside literals and comments is never a placeholder.
+		if strings.Contains(text, "??") || strings.ContainsRune(text, backRefMarker) {
+			return
+		}
+		literals := []string{
+			"'" + strings.ReplaceAll(text, "'", "''") + "'",
+			`"` + strings.ReplaceAll(text, `"`, `""`) + `"`,
+			"E'" + strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "'", `\'`) + "'",
+		}
+		if !strings.Contains(text, "$q$") && !strings.HasSuffix(text, "$q") {
+			literals = append(literals, "$q$"+text+"$q$")
+		}
+		if !strings.Contains(text, "\n") {
+			literals = append(literals, "-- "+text+"\n")
+		}
+		if !strings.Contains(text, "/*") && !strings.Contains(text, "*/") && !strings.HasSuffix(text, "/") && !strings.HasSuffix(text, "*") {
+			literals = append(literals, "/* "+text+" */")
+		}
+		for _, literal := range literals {
+			s, err := dollarPlaceholder("SELECT ?, " + literal + " , ?")
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %v", literal, err)
+			}
+			if want := "SELECT $1, " + literal + " , $2"; s != want {
+				t.Errorf("expected %q, got %q instead", want, s)
+			}
+		}
+	})
+}
+
 func BenchmarkPlaceholdersArray(b *testing.B) {
 	var count = b.N
 	placeholders := make([]string, count)
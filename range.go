@@ -0,0 +1,233 @@
+package pgq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RangeContains is syntactic sugar for use with the range @> operator, which
+// checks if the range or multirange column contains the value, which can be a
+// range or an element.
+// Values can be a SQLizer, such as TstzRange, or are bound as an arg.
+// Ex:
+//
+//	.Where(RangeContains{"during": TstzRange(start, end, "[)")}) == "during @> tstzrange(?, ?, ?)"
+//	.Where(RangeContains{"during": Expr("?::timestamptz", now)}) == "during @> ?::timestamptz"
+type RangeContains map[string]any
+
+func (rc RangeContains) toSQL(opr string) (sql string, args []any, err error) {
+	exprs := make([]string, 0, len(rc))
+	for _, key := range getSortedKeys(rc) {
+		val := rc[key]
+		if val == nil {
+			err = fmt.Errorf("cannot use null with range operators")
+			return
+		}
+		var valSQL string
+		var valArgs []any
+		valSQL, valArgs, err = nestedSQL(newValuePart(val))
+		if err != nil {
+			return
+		}
+		exprs = append(exprs, fmt.Sprintf("%s %s %s", key, opr, valSQL))
+		args = append(args, valArgs...)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+func (rc RangeContains) SQL() (string, []any, error) {
+	return resolvedSQL(rc)
+}
+
+func (rc RangeContains) unfinalizedSQL() (sql string, args []any, err error) {
+	return rc.toSQL("@>")
+}
+
+// RangeContainedBy is syntactic sugar for use with the range <@ operator,
+// which checks if the column is contained by the range.
+// Ex:
+//
+//	.Where(RangeContainedBy{"during": TstzRange(start, end, "")}) == "during <@ tstzrange(?, ?)"
+type RangeContainedBy RangeContains
+
+func (rcb RangeContainedBy) SQL() (string, []any, error) {
+	return resolvedSQL(rcb)
+}
+
+func (rcb RangeContainedBy) unfinalizedSQL() (sql string, args []any, err error) {
+	return RangeContains(rcb).toSQL("<@")
+}
+
+// RangeOverlaps is syntactic sugar for use with the range && operator, which
+// checks if the column and the range have any points in common.
+// Ex:
+//
+//	.Where(RangeOverlaps{"during": TstzRange(start, end, "[)")}) == "during && tstzrange(?, ?, ?)"
+type RangeOverlaps RangeContains
+
+func (ro RangeOverlaps) SQL() (string, []any, error) {
+	return resolvedSQL(ro)
+}
+
+func (ro RangeOverlaps) unfinalizedSQL() (sql string, args []any, err error) {
+	return RangeContains(ro).toSQL("&&")
+}
+
+// RangeAdjacent is syntactic sugar for use with the range -|- operator, which
+// checks if the column and the range are next to each other, without
+// overlapping.
+// Ex:
+//
+//	.Where(RangeAdjacent{"during": TstzRange(start, end, "[)")}) == "during -|- tstzrange(?, ?, ?)"
+type RangeAdjacent RangeContains
+
+func (ra RangeAdjacent) SQL() (string, []any, error) {
+	return resolvedSQL(ra)
+}
+
+func (ra RangeAdjacent) unfinalizedSQL() (sql string, args []any, err error) {
+	return RangeContains(ra).toSQL("-|-")
+}
+
+// RangeLeftOf is syntactic sugar for use with the range << operator, which
+// checks if the column is strictly left of the range.
+// Ex:
+//
+//	.Where(RangeLeftOf{"during": TstzRange(start, nil, "[)")}) == "during << tstzrange(?, ?, ?)"
+type RangeLeftOf RangeContains
+
+func (rl RangeLeftOf) SQL() (string, []any, error) {
+	return resolvedSQL(rl)
+}
+
+func (rl RangeLeftOf) unfinalizedSQL() (sql string, args []any, err error) {
+	return RangeContains(rl).toSQL("<<")
+}
+
+// RangeRightOf is syntactic sugar for use with the range >> operator, which
+// checks if the column is strictly right of the range.
+// Ex:
+//
+//	.Where(RangeRightOf{"during": TstzRange(nil, end, "[)")}) == "during >> tstzrange(?, ?, ?)"
+type RangeRightOf RangeContains
+
+func (rr RangeRightOf) SQL() (string, []any, error) {
+	return resolvedSQL(rr)
+}
+
+func (rr RangeRightOf) unfinalizedSQL() (sql string, args []any, err error) {
+	return RangeContains(rr).toSQL(">>")
+}
+
+type rangeConstructor struct {
+	typ    string
+	lower  any
+	upper  any
+	bounds string
+}
+
+// Range is a constructor of the range type typ, such as "int4range", with the
+// lower and upper bounds bound as args, unless they are SQLizers.
+// A nil bound is unbounded.
+// bounds is "[)", "[]", "(]", or "()", where "[" and "]" are inclusive, and
+// "(" and ")" are exclusive, or empty for the default of "[)".
+//
+// Ex:
+//
+//	Range("int4range", 1, 10, "[]") == "int4range(?, ?, ?)"
+func Range(typ string, lower, upper any, bounds string) SQLizer {
+	return rangeConstructor{typ: typ, lower: lower, upper: upper, bounds: bounds}
+}
+
+// Int4Range is a constructor of an int4range.
+//
+// See Range.
+func Int4Range(lower, upper any, bounds string) SQLizer {
+	return Range("int4range", lower, upper, bounds)
+}
+
+// Int8Range is a constructor of an int8range.
+//
+// See Range.
+func Int8Range(lower, upper any, bounds string) SQLizer {
+	return Range("int8range", lower, upper, bounds)
+}
+
+// NumRange is a constructor of a numrange.
+//
+// See Range.
+func NumRange(lower, upper any, bounds string) SQLizer {
+	return Range("numrange", lower, upper, bounds)
+}
+
+// TsRange is a constructor of a tsrange, a range of timestamp without time
+// zone.
+//
+// See Range.
+func TsRange(lower, upper any, bounds string) SQLizer {
+	return Range("tsrange", lower, upper, bounds)
+}
+
+// TstzRange is a constructor of a tstzrange, a range of timestamp with time
+// zone.
+//
+// See Range.
+func TstzRange(lower, upper any, bounds string) SQLizer {
+	return Range("tstzrange", lower, upper, bounds)
+}
+
+// DateRange is a constructor of a daterange.
+//
+// See Range.
+func DateRange(lower, upper any, bounds string) SQLizer {
+	return Range("daterange", lower, upper, bounds)
+}
+
+func (r rangeConstructor) SQL() (string, []any, error) {
+	return resolvedSQL(r)
+}
+
+func (r rangeConstructor) unfinalizedSQL() (sql string, args []any, err error) {
+	if !isQualifiedName(r.typ) {
+		err = fmt.Errorf("invalid range type %q", r.typ)
+		return
+	}
+	parts := []SQLizer{newValuePart(r.lower), newValuePart(r.upper)}
+	switch r.bounds {
+	case "":
+	case "[)", "[]", "(]", "()":
+		parts = append(parts, Expr("?", r.bounds))
+	default:
+		err = fmt.Errorf("invalid range bounds %q", r.bounds)
+		return
+	}
+	return callSQL(r.typ, parts)
+}
+
+type multirangeConstructor struct {
+	typ    string
+	ranges []SQLizer
+}
+
+// Multirange is a constructor of the multirange type typ, such as
+// "tstzmultirange", with the ranges, or empty if there are none.
+//
+// Ex:
+//
+//	Multirange("datemultirange", DateRange(a, b, ""), DateRange(c, d, "")) == "datemultirange(daterange(?, ?), daterange(?, ?))"
+func Multirange(typ string, ranges ...SQLizer) SQLizer {
+	return multirangeConstructor{typ: typ, ranges: ranges}
+}
+
+func (m multirangeConstructor) SQL() (string, []any, error) {
+	return resolvedSQL(m)
+}
+
+func (m multirangeConstructor) unfinalizedSQL() (sql string, args []any, err error) {
+	if !isQualifiedName(m.typ) {
+		err = fmt.Errorf("invalid multirange type %q", m.typ)
+		return
+	}
+	return callSQL(m.typ, m.ranges)
+}
@@ -0,0 +1,188 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "tstzrange",
+			b:        TstzRange(start, end, "[)"),
+			wantSQL:  "tstzrange(?, ?, ?)",
+			wantArgs: []any{start, end, "[)"},
+		},
+		{
+			name:     "default_bounds",
+			b:        DateRange("2024-01-01", nil, ""),
+			wantSQL:  "daterange(?, ?)",
+			wantArgs: []any{"2024-01-01", nil},
+		},
+		{
+			name:     "expr_bound",
+			b:        TsRange(Expr("now()"), Expr("now() + ?::interval", "1 day"), "[]"),
+			wantSQL:  "tsrange(now(), now() + ?::interval, ?)",
+			wantArgs: []any{"1 day", "[]"},
+		},
+		{
+			name:     "numeric",
+			b:        And{Int4Range(1, 10, "()"), Int8Range(1, 10, "(]"), NumRange(1.5, 2.5, "")},
+			wantSQL:  "(int4range(?, ?, ?) AND int8range(?, ?, ?) AND numrange(?, ?))",
+			wantArgs: []any{1, 10, "()", 1, 10, "(]", 1.5, 2.5},
+		},
+		{
+			name:     "custom_range",
+			b:        Range("scheduling.timerange", "09:00", "17:00", ""),
+			wantSQL:  "scheduling.timerange(?, ?)",
+			wantArgs: []any{"09:00", "17:00"},
+		},
+		{
+			name:     "multirange",
+			b:        Multirange("int4multirange", Int4Range(1, 3, ""), Int4Range(5, 8, "")),
+			wantSQL:  "int4multirange(int4range(?, ?), int4range(?, ?))",
+			wantArgs: []any{1, 3, 5, 8},
+		},
+		{
+			name:    "multirange_empty",
+			b:       Multirange("tstzmultirange"),
+			wantSQL: "tstzmultirange()",
+		},
+		{
+			name:     "contains",
+			b:        RangeContains{"during": TstzRange(start, end, "[)"), "slots": Expr("?::int", 3)},
+			wantSQL:  "during @> tstzrange(?, ?, ?) AND slots @> ?::int",
+			wantArgs: []any{start, end, "[)", 3},
+		},
+		{
+			name:     "contained_by",
+			b:        RangeContainedBy{"during": "[2024-01-01,2024-02-01)"},
+			wantSQL:  "during <@ ?",
+			wantArgs: []any{"[2024-01-01,2024-02-01)"},
+		},
+		{
+			name:     "overlaps",
+			b:        RangeOverlaps{"during": TstzRange(start, end, "")},
+			wantSQL:  "during && tstzrange(?, ?)",
+			wantArgs: []any{start, end},
+		},
+		{
+			name:     "adjacent",
+			b:        RangeAdjacent{"during": TstzRange(start, end, "")},
+			wantSQL:  "during -|- tstzrange(?, ?)",
+			wantArgs: []any{start, end},
+		},
+		{
+			name:     "left_right",
+			b:        And{RangeLeftOf{"during": TstzRange(end, nil, "")}, RangeRightOf{"during": TstzRange(nil, start, "")}},
+			wantSQL:  "(during << tstzrange(?, ?) AND during >> tstzrange(?, ?))",
+			wantArgs: []any{end, nil, nil, start},
+		},
+		{
+			name: "select",
+			b: Select("id").
+				From("bookings").
+				Where("room_id = ?", 7).
+				Where(RangeOverlaps{"during": TstzRange(start, end, "[)")}).
+				Where(Not(RangeAdjacent{"during": TstzRange(start, end, "[)")})),
+			wantSQL:  "SELECT id FROM bookings WHERE room_id = $1 AND during && tstzrange($2, $3, $4) AND NOT (during -|- tstzrange($5, $6, $7))",
+			wantArgs: []any{7, start, end, "[)", start, end, "[)"},
+		},
+		{
+			name: "update",
+			b: Update("bookings").
+				Set("during", TstzRange(start, end, "[)")).
+				Where(Eq{"id": 1}),
+			wantSQL:  "UPDATE bookings SET during = tstzrange($1, $2, $3) WHERE id = $4",
+			wantArgs: []any{start, end, "[)", 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestRangeErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "bounds",
+			b:    Int4Range(1, 2, "[["),
+			want: `invalid range bounds "[["`,
+		},
+		{
+			name: "type",
+			b:    Range("int4range(1, 2) --", 1, 2, ""),
+			want: `invalid range type "int4range(1, 2) --"`,
+		},
+		{
+			name: "multirange_type",
+			b:    Multirange(""),
+			want: `invalid multirange type ""`,
+		},
+		{
+			name: "contains_null",
+			b:    RangeContains{"during": nil},
+			want: "cannot use null with range operators",
+		},
+		{
+			name: "overlaps_range",
+			b:    RangeOverlaps{"during": TstzRange(nil, nil, "{}")},
+			want: `invalid range bounds "{}"`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleRangeOverlaps() {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	sql, args, _ := Select("id").
+		From("bookings").
+		Where(Eq{"room_id": 7}).
+		Where(RangeOverlaps{"during": TstzRange(start, end, "[)")}).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id FROM bookings WHERE room_id = $1 AND during && tstzrange($2, $3, $4)
+	// [7 2024-01-01 09:00:00 +0000 UTC 2024-01-01 10:00:00 +0000 UTC [)]
+}
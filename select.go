@@ -2,25 +2,33 @@ package pgq
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
 // SelectBuilder builds SQL SELECT statements.
 type SelectBuilder struct {
-	placeholder  placeholder
 	prefixes     []SQLizer
+	ctes         []CTE
 	options      []string
+	distinctOn   []SQLizer
 	columns      []SQLizer
 	from         SQLizer
 	joins        []SQLizer
 	whereParts   []SQLizer
-	groupBys     []string
+	groupBys     []SQLizer
 	havingParts  []SQLizer
+	windows      []namedWindow
 	orderByParts []SQLizer
-	limit        string
-	offset       string
+	limit        SQLizer
+	offset       SQLizer
+	fetch        *fetchClause
+	keyset       *KeysetBuilder
+	locks        []lockClause
 	suffixes     []SQLizer
+	setOps       []setOperation
 }
 
 // SQL builds the query into a SQL string and bound args.
@@ -30,19 +38,33 @@ func (b SelectBuilder) SQL() (sqlStr string, args []any, err error) {
 		return
 	}
 
-	f := b.placeholder
-	if f == nil {
-		f = dollarPlaceholder
-	}
-	sqlStr, err = f(sqlStr)
+	sqlStr, err = dollarPlaceholder(sqlStr)
 	return
 }
 
 func (b SelectBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
-	if len(b.columns) == 0 {
+	if b.keyset != nil {
+		if b, err = b.applyKeyset(); err != nil {
+			return
+		}
+	}
+	if len(b.setOps) > 0 && b.hasSelectCore() {
+		err = errors.New("set operations cannot have result columns, FROM, WHERE, GROUP BY, HAVING, or WINDOW clauses; use a subquery instead")
+		return
+	}
+	if len(b.setOps) == 0 && len(b.columns) == 0 {
 		err = fmt.Errorf("select statements must have at least one result column")
 		return
 	}
+	if err = b.validateLocks(); err != nil {
+		return
+	}
+	if err = b.validateDistinctOn(); err != nil {
+		return
+	}
+	if err = b.validateFetch(); err != nil {
+		return
+	}
 
 	sql := &bytes.Buffer{}
 
@@ -55,8 +77,81 @@ func (b SelectBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" ")
 	}
 
+	args, err = appendCTEs(b.ctes, sql, args)
+	if err != nil {
+		return
+	}
+
+	if len(b.setOps) > 0 {
+		args, err = appendSetOperations(b.setOps, sql, args)
+	} else {
+		args, err = b.appendSelectCoreToSQL(sql, args)
+	}
+	if err != nil {
+		return
+	}
+
+	if len(b.orderByParts) > 0 {
+		sql.WriteString(" ORDER BY ")
+		args, err = appendSQL(b.orderByParts, sql, ", ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if b.limit != nil {
+		sql.WriteString(" LIMIT ")
+		args, err = appendSQL([]SQLizer{b.limit}, sql, "", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if b.offset != nil {
+		sql.WriteString(" OFFSET ")
+		args, err = appendSQL([]SQLizer{b.offset}, sql, "", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if b.fetch != nil {
+		args, err = b.fetch.appendToSQL(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
+	b.appendLocksToSQL(sql)
+
+	if len(b.suffixes) > 0 {
+		sql.WriteString(" ")
+
+		args, err = appendSQL(b.suffixes, sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// appendSelectCoreToSQL writes the SELECT list and the FROM, WHERE, GROUP BY, HAVING, and WINDOW clauses.
+func (b SelectBuilder) appendSelectCoreToSQL(sql *bytes.Buffer, args []any) ([]any, error) {
+	var err error
+
 	sql.WriteString("SELECT ")
 
+	if len(b.distinctOn) > 0 {
+		sql.WriteString("DISTINCT ON (")
+		args, err = appendSQL(b.distinctOn, sql, ", ", args)
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(") ")
+	}
+
 	if len(b.options) > 0 {
 		sql.WriteString(strings.Join(b.options, " "))
 		sql.WriteString(" ")
@@ -65,7 +160,7 @@ func (b SelectBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 	if len(b.columns) > 0 {
 		args, err = appendSQL(b.columns, sql, ", ", args)
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 
@@ -73,7 +168,7 @@ func (b SelectBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" FROM ")
 		args, err = appendSQL([]SQLizer{b.from}, sql, "", args)
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 
@@ -81,7 +176,7 @@ func (b SelectBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" ")
 		args, err = appendSQL(b.joins, sql, " ", args)
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 
@@ -89,52 +184,34 @@ func (b SelectBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" WHERE ")
 		args, err = appendSQL(b.whereParts, sql, " AND ", args)
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 
 	if len(b.groupBys) > 0 {
 		sql.WriteString(" GROUP BY ")
-		sql.WriteString(strings.Join(b.groupBys, ", "))
+		args, err = appendSQL(b.groupBys, sql, ", ", args)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if len(b.havingParts) > 0 {
 		sql.WriteString(" HAVING ")
 		args, err = appendSQL(b.havingParts, sql, " AND ", args)
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 
-	if len(b.orderByParts) > 0 {
-		sql.WriteString(" ORDER BY ")
-		args, err = appendSQL(b.orderByParts, sql, ", ", args)
-		if err != nil {
-			return
-		}
-	}
-
-	if b.limit != "" {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(b.limit)
-	}
-
-	if b.offset != "" {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(b.offset)
-	}
-
-	if len(b.suffixes) > 0 {
-		sql.WriteString(" ")
-
-		args, err = appendSQL(b.suffixes, sql, " ", args)
+	if len(b.windows) > 0 {
+		args, err = appendWindows(b.windows, sql, args)
 		if err != nil {
-			return
+			return nil, err
 		}
 	}
 
-	sqlStr = sql.String()
-	return
+	return args, nil
 }
 
 // MustSQL builds the query into a SQL string and bound args.
@@ -158,11 +235,49 @@ func (b SelectBuilder) PrefixExpr(expr SQLizer) SelectBuilder {
 	return b
 }
 
+// With adds a common table expression to the WITH clause of the query.
+func (b SelectBuilder) With(name string, expr SQLizer) SelectBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr})
+}
+
+// WithRecursive adds a common table expression to the WITH clause of the query,
+// turning it into WITH RECURSIVE.
+func (b SelectBuilder) WithRecursive(name string, expr SQLizer) SelectBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr, Recursive: true})
+}
+
+// WithCTE adds a common table expression to the WITH clause of the query.
+func (b SelectBuilder) WithCTE(cte CTE) SelectBuilder {
+	b.ctes = append(b.ctes, cte)
+	return b
+}
+
 // Distinct adds a DISTINCT clause to the query.
 func (b SelectBuilder) Distinct() SelectBuilder {
 	return b.Options("DISTINCT")
 }
 
+// DistinctOn adds a DISTINCT ON clause to the query, keeping only the first
+// row of each set of rows where the given expressions evaluate to equal.
+//
+// Each expression can be a string or a SQLizer, such as Expr with bound args.
+//
+// PostgreSQL requires the DISTINCT ON expressions to match the leftmost
+// ORDER BY expressions, and SQL returns an error if they don't. Expressions are
+// compared by their SQL, so they must be written the same way in both clauses.
+// Expressions with bound args never match, as each clause gets its own
+// placeholders.
+//
+// Ex:
+//
+//	Select("location", "time", "report").DistinctOn("location").From("weather_reports").OrderBy("location", "time DESC")
+func (b SelectBuilder) DistinctOn(exprs ...any) SelectBuilder {
+	for _, expr := range exprs {
+		b.distinctOn = append(b.distinctOn, newPart(expr))
+	}
+	return b
+}
+
 // Options adds select option to the query
 func (b SelectBuilder) Options(options ...string) SelectBuilder {
 	b.options = append(b.options, options...)
@@ -179,6 +294,16 @@ func (b SelectBuilder) Columns(columns ...string) SelectBuilder {
 	return b
 }
 
+// ColumnsIdent adds result columns to the query, quoted as identifiers.
+//
+// See QuoteIdent.
+func (b SelectBuilder) ColumnsIdent(columns ...string) SelectBuilder {
+	for _, column := range columns {
+		b.columns = append(b.columns, Ident(column))
+	}
+	return b
+}
+
 // RemoveColumns remove all columns from query.
 // Must add a new column with Column or Columns methods, otherwise
 // return a error.
@@ -203,11 +328,16 @@ func (b SelectBuilder) From(from string) SelectBuilder {
 	return b
 }
 
+// FromIdent sets the FROM clause of the query to a table, quoted as an identifier.
+//
+// See QuoteIdent.
+func (b SelectBuilder) FromIdent(from string) SelectBuilder {
+	b.from = Ident(from)
+	return b
+}
+
 // FromSelect sets a subquery into the FROM clause of the query.
 func (b SelectBuilder) FromSelect(from SelectBuilder, alias string) SelectBuilder {
-	// Prevent misnumbered parameters in nested selects
-	// See https://github.com/Masterminds/squirrel/issues/183
-	from.placeholder = questionPlaceholder
 	b.from = Alias{
 		Expr: from,
 		As:   alias,
@@ -216,6 +346,7 @@ func (b SelectBuilder) FromSelect(from SelectBuilder, alias string) SelectBuilde
 }
 
 // JoinClause adds a join clause to the query.
+// pred can be a string or a SQLizer, such as JoinBuilder.
 func (b SelectBuilder) JoinClause(pred any, args ...any) SelectBuilder {
 	b.joins = append(b.joins, newPart(pred, args...))
 	return b
@@ -241,6 +372,11 @@ func (b SelectBuilder) InnerJoin(join string, rest ...any) SelectBuilder {
 	return b.JoinClause("INNER JOIN "+join, rest...)
 }
 
+// FullJoin adds a FULL OUTER JOIN clause to the query.
+func (b SelectBuilder) FullJoin(join string, rest ...any) SelectBuilder {
+	return b.JoinClause("FULL OUTER JOIN "+join, rest...)
+}
+
 // CrossJoin adds a CROSS JOIN clause to the query.
 func (b SelectBuilder) CrossJoin(join string, rest ...any) SelectBuilder {
 	return b.JoinClause("CROSS JOIN "+join, rest...)
@@ -276,7 +412,17 @@ func (b SelectBuilder) Where(pred any, args ...any) SelectBuilder {
 
 // GroupBy adds GROUP BY expressions to the query.
 func (b SelectBuilder) GroupBy(groupBys ...string) SelectBuilder {
-	b.groupBys = append(b.groupBys, groupBys...)
+	for _, groupBy := range groupBys {
+		b = b.GroupByClause(groupBy)
+	}
+	return b
+}
+
+// GroupByClause adds a GROUP BY expression or grouping element to the query.
+//
+// See GroupByRollup, GroupByCube, and GroupByGroupingSets.
+func (b SelectBuilder) GroupByClause(pred any, args ...any) SelectBuilder {
+	b.groupBys = append(b.groupBys, newPart(pred, args...))
 	return b
 }
 
@@ -311,25 +457,49 @@ func (b SelectBuilder) RemoveOrderBy() SelectBuilder {
 
 // Limit sets a LIMIT clause on the query.
 func (b SelectBuilder) Limit(limit uint64) SelectBuilder {
-	b.limit = fmt.Sprintf("%d", limit)
+	b.limit = newPart(strconv.FormatUint(limit, 10))
+	return b
+}
+
+// LimitExpr sets a LIMIT clause on the query with a bound arg, so the query
+// is the same for any limit, such as for prepared statements.
+// limit is bound as an arg, unless it is a SQLizer.
+func (b SelectBuilder) LimitExpr(limit any) SelectBuilder {
+	b.limit = newValuePart(limit)
+	return b
+}
+
+// LimitAll sets a LIMIT ALL clause on the query, which is the same as no limit.
+func (b SelectBuilder) LimitAll() SelectBuilder {
+	b.limit = newPart("ALL")
 	return b
 }
 
-// Limit ALL allows to access all records with limit
+// RemoveLimit removes the LIMIT and FETCH clauses.
 func (b SelectBuilder) RemoveLimit() SelectBuilder {
-	b.limit = ""
+	b.limit = nil
+	b.fetch = nil
 	return b
 }
 
 // Offset sets a OFFSET clause on the query.
 func (b SelectBuilder) Offset(offset uint64) SelectBuilder {
-	b.offset = fmt.Sprintf("%d", offset)
+	b.offset = newPart(strconv.FormatUint(offset, 10))
+	return b
+}
+
+// OffsetExpr sets an OFFSET clause on the query with a bound arg.
+// offset is bound as an arg, unless it is a SQLizer.
+//
+// See LimitExpr.
+func (b SelectBuilder) OffsetExpr(offset any) SelectBuilder {
+	b.offset = newValuePart(offset)
 	return b
 }
 
 // RemoveOffset removes OFFSET clause.
 func (b SelectBuilder) RemoveOffset() SelectBuilder {
-	b.offset = ""
+	b.offset = nil
 	return b
 }
 
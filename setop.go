@@ -0,0 +1,190 @@
+package pgq
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	setOpUnion        = "UNION"
+	setOpUnionAll     = "UNION ALL"
+	setOpIntersect    = "INTERSECT"
+	setOpIntersectAll = "INTERSECT ALL"
+	setOpExcept       = "EXCEPT"
+	setOpExceptAll    = "EXCEPT ALL"
+)
+
+// setOperation is an operand of a compound query, combined with the previous
+// operands by op. The op of the first operand is empty.
+type setOperation struct {
+	op    string
+	query SelectBuilder
+}
+
+func appendSetOperations(ops []setOperation, sql *bytes.Buffer, args []any) ([]any, error) {
+	for _, o := range ops {
+		if o.op != "" {
+			sql.WriteString(" ")
+			sql.WriteString(o.op)
+			sql.WriteString(" ")
+		}
+
+		if len(o.query.locks) > 0 {
+			return nil, fmt.Errorf("%s cannot be used with UNION, INTERSECT, or EXCEPT", o.query.locks[0].strength)
+		}
+
+		querySQL, queryArgs, err := o.query.unfinalizedSQL()
+		if err != nil {
+			return nil, err
+		}
+		if o.query.needsParens() {
+			querySQL = "(" + querySQL + ")"
+		}
+		sql.WriteString(querySQL)
+		args = append(args, queryArgs...)
+	}
+	return args, nil
+}
+
+// hasSelectCore reports whether b has any of the clauses of a simple SELECT,
+// which a compound query cannot have.
+func (b SelectBuilder) hasSelectCore() bool {
+	return len(b.options) > 0 || len(b.distinctOn) > 0 || len(b.columns) > 0 || b.from != nil || len(b.joins) > 0 ||
+		len(b.whereParts) > 0 || len(b.groupBys) > 0 || len(b.havingParts) > 0 || len(b.windows) > 0
+}
+
+// hasTrailingClauses reports whether b has clauses that would apply to the
+// whole result of a compound query if b was used as its first operand.
+func (b SelectBuilder) hasTrailingClauses() bool {
+	return len(b.orderByParts) > 0 || b.limit != nil || b.offset != nil || b.fetch != nil ||
+		len(b.locks) > 0 || len(b.suffixes) > 0 || b.keyset != nil
+}
+
+// needsParens reports whether b must be parenthesized when used as an operand
+// of a compound query.
+func (b SelectBuilder) needsParens() bool {
+	return len(b.setOps) > 0 || len(b.prefixes) > 0 || len(b.ctes) > 0 || b.hasTrailingClauses()
+}
+
+func (b SelectBuilder) setOperation(op string, queries []SelectBuilder) SelectBuilder {
+	if len(b.setOps) == 0 || b.hasTrailingClauses() || (isIntersect(op) && !b.onlyIntersects()) {
+		// INTERSECT binds more tightly than UNION and EXCEPT, so the
+		// compound query built so far is nested to keep the left-to-right order.
+		b = SelectBuilder{setOps: []setOperation{{query: b}}}
+	}
+	for _, q := range queries {
+		b.setOps = append(b.setOps, setOperation{op: op, query: q})
+	}
+	return b
+}
+
+func (b SelectBuilder) onlyIntersects() bool {
+	for _, o := range b.setOps[1:] {
+		if !isIntersect(o.op) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIntersect(op string) bool {
+	return op == setOpIntersect || op == setOpIntersectAll
+}
+
+// Union combines the result of the query with the result of the given queries with UNION.
+//
+// The returned SelectBuilder is a compound query: ORDER BY, LIMIT, and OFFSET
+// clauses added to it apply to the combined result, and it cannot have result
+// columns, FROM, WHERE, GROUP BY, HAVING, or WINDOW clauses of its own.
+//
+// Ex:
+//
+//	Select("a").From("t1").Union(Select("a").From("t2")).OrderBy("a").Limit(10)
+func (b SelectBuilder) Union(queries ...SelectBuilder) SelectBuilder {
+	return b.setOperation(setOpUnion, queries)
+}
+
+// UnionAll combines the result of the query with the result of the given queries with UNION ALL.
+//
+// See Union.
+func (b SelectBuilder) UnionAll(queries ...SelectBuilder) SelectBuilder {
+	return b.setOperation(setOpUnionAll, queries)
+}
+
+// Intersect combines the result of the query with the result of the given queries with INTERSECT.
+//
+// See Union.
+func (b SelectBuilder) Intersect(queries ...SelectBuilder) SelectBuilder {
+	return b.setOperation(setOpIntersect, queries)
+}
+
+// IntersectAll combines the result of the query with the result of the given queries with INTERSECT ALL.
+//
+// See Union.
+func (b SelectBuilder) IntersectAll(queries ...SelectBuilder) SelectBuilder {
+	return b.setOperation(setOpIntersectAll, queries)
+}
+
+// Except removes the result of the given queries from the result of the query with EXCEPT.
+//
+// See Union.
+func (b SelectBuilder) Except(queries ...SelectBuilder) SelectBuilder {
+	return b.setOperation(setOpExcept, queries)
+}
+
+// ExceptAll removes the result of the given queries from the result of the query with EXCEPT ALL.
+//
+// See Union.
+func (b SelectBuilder) ExceptAll(queries ...SelectBuilder) SelectBuilder {
+	return b.setOperation(setOpExceptAll, queries)
+}
+
+func compound(op string, queries []SelectBuilder) SelectBuilder {
+	if len(queries) == 0 {
+		return SelectBuilder{}
+	}
+	b := SelectBuilder{setOps: []setOperation{{query: queries[0]}}}
+	return b.setOperation(op, queries[1:])
+}
+
+// Union returns a compound query combining the results of queries with UNION.
+//
+// See SelectBuilder.Union.
+func Union(queries ...SelectBuilder) SelectBuilder {
+	return compound(setOpUnion, queries)
+}
+
+// UnionAll returns a compound query combining the results of queries with UNION ALL.
+//
+// See SelectBuilder.Union.
+func UnionAll(queries ...SelectBuilder) SelectBuilder {
+	return compound(setOpUnionAll, queries)
+}
+
+// Intersect returns a compound query combining the results of queries with INTERSECT.
+//
+// See SelectBuilder.Union.
+func Intersect(queries ...SelectBuilder) SelectBuilder {
+	return compound(setOpIntersect, queries)
+}
+
+// IntersectAll returns a compound query combining the results of queries with INTERSECT ALL.
+//
+// See SelectBuilder.Union.
+func IntersectAll(queries ...SelectBuilder) SelectBuilder {
+	return compound(setOpIntersectAll, queries)
+}
+
+// Except returns a compound query removing the results of the following queries from the first one with EXCEPT.
+//
+// See SelectBuilder.Union.
+func Except(queries ...SelectBuilder) SelectBuilder {
+	return compound(setOpExcept, queries)
+}
+
+// ExceptAll returns a compound query removing the results of the following queries from the first one with EXCEPT ALL.
+//
+// See SelectBuilder.Union.
+func ExceptAll(queries ...SelectBuilder) SelectBuilder {
+	return compound(setOpExceptAll, queries)
+}
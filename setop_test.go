@@ -0,0 +1,184 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	t.Parallel()
+	a := Select("id").From("a").Where("x = ?", 1)
+	b := Select("id").From("b").Where("y = ?", 2)
+	c := Select("id").From("c").Where("z = ?", 3)
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "union",
+			b:        a.Union(b),
+			wantSQL:  "SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "union_all_many",
+			b:        UnionAll(a, b, c),
+			wantSQL:  "SELECT id FROM a WHERE x = $1 UNION ALL SELECT id FROM b WHERE y = $2 UNION ALL SELECT id FROM c WHERE z = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "intersect",
+			b:        Intersect(a, b).IntersectAll(c),
+			wantSQL:  "SELECT id FROM a WHERE x = $1 INTERSECT SELECT id FROM b WHERE y = $2 INTERSECT ALL SELECT id FROM c WHERE z = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "except",
+			b:        Except(a, b).ExceptAll(c),
+			wantSQL:  "SELECT id FROM a WHERE x = $1 EXCEPT SELECT id FROM b WHERE y = $2 EXCEPT ALL SELECT id FROM c WHERE z = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "union_then_intersect",
+			b:        a.Union(b).Intersect(c),
+			wantSQL:  "(SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2) INTERSECT SELECT id FROM c WHERE z = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "intersect_then_union",
+			b:        a.Intersect(b).Union(c),
+			wantSQL:  "SELECT id FROM a WHERE x = $1 INTERSECT SELECT id FROM b WHERE y = $2 UNION SELECT id FROM c WHERE z = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "compound_operand",
+			b:        a.Except(b.Union(c)),
+			wantSQL:  "SELECT id FROM a WHERE x = $1 EXCEPT (SELECT id FROM b WHERE y = $2 UNION SELECT id FROM c WHERE z = $3)",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name: "trailing_clauses",
+			b:    a.Union(b).OrderBy("id DESC").Limit(10).Offset(20),
+			wantSQL: "SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2 " +
+				"ORDER BY id DESC LIMIT 10 OFFSET 20",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "operands_with_trailing_clauses",
+			b:    a.OrderBy("id").Limit(1).UnionAll(b.OrderBy("id DESC").Limit(1)).OrderBy("id"),
+			wantSQL: "(SELECT id FROM a WHERE x = $1 ORDER BY id LIMIT 1) " +
+				"UNION ALL (SELECT id FROM b WHERE y = $2 ORDER BY id DESC LIMIT 1) ORDER BY id",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "union_after_limit",
+			b:    a.Union(b).Limit(5).Union(c),
+			wantSQL: "(SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2 LIMIT 5) " +
+				"UNION SELECT id FROM c WHERE z = $3",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "with",
+			b:        Union(Select("id").From("r"), b).With("r", Expr("SELECT ?", 0)),
+			wantSQL:  "WITH r AS (SELECT $1) SELECT id FROM r UNION SELECT id FROM b WHERE y = $2",
+			wantArgs: []any{0, 2},
+		},
+		{
+			name:     "from_select",
+			b:        Select("count(*)").FromSelect(a.Union(b), "u").Where("id > ?", 4),
+			wantSQL:  "SELECT count(*) FROM (SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2) AS u WHERE id > $3",
+			wantArgs: []any{1, 2, 4},
+		},
+		{
+			name:     "insert_select",
+			b:        Insert("t").Columns("id").Select(a.Union(b)).Suffix("RETURNING ?", 4),
+			wantSQL:  "INSERT INTO t (id) SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2 RETURNING $3",
+			wantArgs: []any{1, 2, 4},
+		},
+		{
+			name:     "cte",
+			b:        Select("*").With("u", a.Union(b)).From("u").Where("id > ?", 4),
+			wantSQL:  "WITH u AS (SELECT id FROM a WHERE x = $1 UNION SELECT id FROM b WHERE y = $2) SELECT * FROM u WHERE id > $3",
+			wantArgs: []any{1, 2, 4},
+		},
+		{
+			name:     "expr",
+			b:        Select("*").From("t").Where("k = ?", 0).Where(Expr("id IN (?)", a.Union(b))),
+			wantSQL:  "SELECT * FROM t WHERE k = $1 AND id IN (SELECT id FROM a WHERE x = $2 UNION SELECT id FROM b WHERE y = $3)",
+			wantArgs: []any{0, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestSetOperationsErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SelectBuilder
+		want string
+	}{
+		{
+			name: "where",
+			b:    Select("a").Union(Select("b")).Where("a > 0"),
+			want: "set operations cannot have result columns, FROM, WHERE, GROUP BY, HAVING, or WINDOW clauses; use a subquery instead",
+		},
+		{
+			name: "columns",
+			b:    Select("a").Union(Select("b")).Columns("c"),
+			want: "set operations cannot have result columns, FROM, WHERE, GROUP BY, HAVING, or WINDOW clauses; use a subquery instead",
+		},
+		{
+			name: "operand",
+			b:    Select("a").Union(Select()),
+			want: "select statements must have at least one result column",
+		},
+		{
+			name: "empty",
+			b:    Union(),
+			want: "select statements must have at least one result column",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleUnion() {
+	sql, args, _ := Union(
+		Select("id", "name").From("customers").Where("active = ?", true),
+		Select("id", "name").From("suppliers").Where("active = ?", true),
+	).OrderBy("name").Limit(10).SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, name FROM customers WHERE active = $1 UNION SELECT id, name FROM suppliers WHERE active = $2 ORDER BY name LIMIT 10
+	// [true true]
+}
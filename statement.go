@@ -1,20 +1,32 @@
 package pgq
 
-// StatementBuilder for WHERE parts.
+// StatementBuilder for WHERE parts and common table expressions.
 type StatementBuilder struct {
+	ctes       []CTE
 	whereParts []SQLizer
 }
 
 // Select returns a SelectBuilder for this StatementBuilder.
 func (b StatementBuilder) Select(columns ...string) SelectBuilder {
 	builder := SelectBuilder{}.Columns(columns...)
+	builder.ctes = b.ctes
 	builder.whereParts = b.whereParts
 	return builder
 }
 
+// Insert returns a InsertBuilder for this StatementBuilder.
+//
+// WHERE parts are not used by INSERT statements.
+func (b StatementBuilder) Insert(into string) InsertBuilder {
+	builder := InsertBuilder{}.Into(into)
+	builder.ctes = b.ctes
+	return builder
+}
+
 // Update returns a UpdateBuilder for this StatementBuilder.
 func (b StatementBuilder) Update(table string) UpdateBuilder {
 	builder := UpdateBuilder{}.Table(table)
+	builder.ctes = b.ctes
 	builder.whereParts = b.whereParts
 	return builder
 }
@@ -22,10 +34,40 @@ func (b StatementBuilder) Update(table string) UpdateBuilder {
 // Delete returns a DeleteBuilder for this StatementBuilder.
 func (b StatementBuilder) Delete(from string) DeleteBuilder {
 	builder := DeleteBuilder{}.From(from)
+	builder.ctes = b.ctes
 	builder.whereParts = b.whereParts
 	return builder
 }
 
+// Merge returns a MergeBuilder for this StatementBuilder.
+//
+// WHERE parts are not used by MERGE statements.
+func (b StatementBuilder) Merge(into string) MergeBuilder {
+	builder := MergeBuilder{}.Into(into)
+	builder.ctes = b.ctes
+	return builder
+}
+
+// With adds a common table expression to the statements created by this StatementBuilder.
+//
+// See SelectBuilder.With for more information.
+func (b StatementBuilder) With(name string, expr SQLizer) StatementBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr})
+}
+
+// WithRecursive adds a recursive common table expression to the statements created by this StatementBuilder.
+//
+// See SelectBuilder.WithRecursive for more information.
+func (b StatementBuilder) WithRecursive(name string, expr SQLizer) StatementBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr, Recursive: true})
+}
+
+// WithCTE adds a common table expression to the statements created by this StatementBuilder.
+func (b StatementBuilder) WithCTE(cte CTE) StatementBuilder {
+	b.ctes = append(b.ctes, cte)
+	return b
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -39,6 +81,24 @@ func Statement() StatementBuilder {
 	return StatementBuilder{}
 }
 
+// With returns a new StatementBuilder with a common table expression,
+// which can be used to create SELECT, INSERT, UPDATE, and DELETE statements.
+//
+// Ex:
+//
+//	With("moved", Delete("a").Where("x = ?", 1).Returning("*")).
+//		Insert("b").Select(Select("*").From("moved"))
+func With(name string, expr SQLizer) StatementBuilder {
+	return StatementBuilder{}.With(name, expr)
+}
+
+// WithRecursive returns a new StatementBuilder with a recursive common table expression.
+//
+// See With.
+func WithRecursive(name string, expr SQLizer) StatementBuilder {
+	return StatementBuilder{}.WithRecursive(name, expr)
+}
+
 // Select returns a new SelectBuilder, optionally setting some result columns.
 //
 // See SelectBuilder.Columns.
@@ -53,14 +113,19 @@ func Insert(into string) InsertBuilder {
 	return InsertBuilder{into: into}
 }
 
-// Replace returns a new InsertBuilder with the statement keyword set to
-// "REPLACE" and with the given table name.
+// Replace returns a new InsertBuilder for a full-row upsert into the given table.
+//
+// On conflict, every inserted column outside of the conflict target is
+// updated to the value proposed for insertion, so the conflict target must be
+// set with OnConflict or OnConflictOnConstraint:
+//
+//	Replace("users").Columns("id", "name").Values(1, "Alice").OnConflict("id")
+//
+// renders INSERT INTO users (id,name) VALUES ($1,$2) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name
 //
 // See InsertBuilder.Into.
 func Replace(into string) InsertBuilder {
-	builder := InsertBuilder{}
-	builder.verb = "REPLACE"
-	return builder.Into(into)
+	return InsertBuilder{into: into, replace: true}
 }
 
 // Update returns a new UpdateBuilder with the given table name.
@@ -77,6 +142,13 @@ func Delete(from string) DeleteBuilder {
 	return DeleteBuilder{from: from}
 }
 
+// Merge returns a new MergeBuilder with the given target table name.
+//
+// See MergeBuilder.Into.
+func Merge(into string) MergeBuilder {
+	return MergeBuilder{into: into}
+}
+
 // Case returns a new CaseBuilder
 // "what" represents case value
 func Case(what ...any) CaseBuilder {
@@ -0,0 +1,168 @@
+package pgq
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// tsFunc is a text search function call, with an optional text search
+// configuration bound as the first arg.
+type tsFunc struct {
+	name   string
+	config string
+	args   []SQLizer
+}
+
+func newTSFunc(name, config string, args ...any) tsFunc {
+	f := tsFunc{name: name, config: config}
+	for _, arg := range args {
+		f.args = append(f.args, newPart(arg))
+	}
+	return f
+}
+
+func (f tsFunc) SQL() (string, []any, error) {
+	return resolvedSQL(f)
+}
+
+func (f tsFunc) unfinalizedSQL() (sql string, args []any, err error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(f.name)
+	buf.WriteString("(")
+	if f.config != "" {
+		buf.WriteString("?::regconfig, ")
+		args = append(args, f.config)
+	}
+	for i, part := range f.args {
+		var partSQL string
+		var partArgs []any
+		partSQL, partArgs, err = nestedSQL(part)
+		if err != nil {
+			return
+		}
+		if partSQL == "" {
+			err = fmt.Errorf("%s arguments cannot be empty", f.name)
+			return
+		}
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(partSQL)
+		args = append(args, partArgs...)
+	}
+	buf.WriteString(")")
+	sql = buf.String()
+	return
+}
+
+// ToTSVector is the to_tsvector(config, document) function, which converts
+// the document to a tsvector, for use with Match.
+// config is the text search configuration, such as "english", and is bound as
+// an arg, or omitted if empty to use default_text_search_config.
+// document is a column or expression string, or a SQLizer.
+//
+// Ex:
+//
+//	ToTSVector("english", "title || ' ' || body") == "to_tsvector(?::regconfig, title || ' ' || body)"
+func ToTSVector(config string, document any) SQLizer {
+	return newTSFunc("to_tsvector", config, document)
+}
+
+// WebsearchToTSQuery is the websearch_to_tsquery(config, query) function,
+// which converts the query to a tsquery using the syntax of web search
+// engines, with quoted phrases, OR, and - for negation.
+// The query is bound as an arg, so it is safe to use with user input.
+//
+// Ex:
+//
+//	WebsearchToTSQuery("english", `"sad cat" or fat -rat`) == "websearch_to_tsquery(?::regconfig, ?)"
+func WebsearchToTSQuery(config, query string) SQLizer {
+	return newTSFunc("websearch_to_tsquery", config, Expr("?", query))
+}
+
+// PlainToTSQuery is the plainto_tsquery(config, query) function, which
+// converts the query to a tsquery matching all its words.
+//
+// See WebsearchToTSQuery.
+func PlainToTSQuery(config, query string) SQLizer {
+	return newTSFunc("plainto_tsquery", config, Expr("?", query))
+}
+
+// PhraseToTSQuery is the phraseto_tsquery(config, query) function, which
+// converts the query to a tsquery matching its words as a phrase.
+//
+// See WebsearchToTSQuery.
+func PhraseToTSQuery(config, query string) SQLizer {
+	return newTSFunc("phraseto_tsquery", config, Expr("?", query))
+}
+
+type tsMatch struct {
+	vector SQLizer
+	query  SQLizer
+}
+
+// Match is the vector @@ query predicate, which is true if the tsvector
+// matches the tsquery.
+// vector and query are column or expression strings, or SQLizers.
+//
+// Ex:
+//
+//	.Where(Match("search_vector", WebsearchToTSQuery("english", q)))
+//	// search_vector @@ websearch_to_tsquery(?::regconfig, ?)
+func Match(vector, query any) SQLizer {
+	return tsMatch{vector: newPart(vector), query: newPart(query)}
+}
+
+func (m tsMatch) SQL() (string, []any, error) {
+	return resolvedSQL(m)
+}
+
+func (m tsMatch) unfinalizedSQL() (sql string, args []any, err error) {
+	vector, args, err := nestedSQL(m.vector)
+	if err != nil {
+		return
+	}
+	query, queryArgs, err := nestedSQL(m.query)
+	if err != nil {
+		return
+	}
+	if vector == "" || query == "" {
+		err = fmt.Errorf("text search matches must have a vector and a query")
+		return
+	}
+	sql = fmt.Sprintf("%s @@ %s", vector, query)
+	args = append(args, queryArgs...)
+	return
+}
+
+// TSRank is the ts_rank(vector, query) function, which ranks how well the
+// tsvector matches the tsquery by the frequency of the matching lexemes.
+//
+// Ex:
+//
+//	.Column(Alias{Expr: TSRank("search_vector", query), As: "rank"})
+func TSRank(vector, query any) SQLizer {
+	return newTSFunc("ts_rank", "", vector, query)
+}
+
+// TSRankCD is the ts_rank_cd(vector, query) function, which is like TSRank,
+// but also considers the proximity of the matching lexemes.
+func TSRankCD(vector, query any) SQLizer {
+	return newTSFunc("ts_rank_cd", "", vector, query)
+}
+
+// TSHeadline is the ts_headline(config, document, query, options) function,
+// which returns an excerpt of the document with the query terms highlighted.
+// options, such as "MaxWords=35, MinWords=15", are bound as an arg, or omitted
+// if empty.
+//
+// Ex:
+//
+//	TSHeadline("english", "body", PlainToTSQuery("english", q), "StartSel=<b>, StopSel=</b>")
+//	// ts_headline(?::regconfig, body, plainto_tsquery(?::regconfig, ?), ?)
+func TSHeadline(config string, document, query any, options string) SQLizer {
+	if options == "" {
+		return newTSFunc("ts_headline", config, document, query)
+	}
+	return newTSFunc("ts_headline", config, document, query, Expr("?", options))
+}
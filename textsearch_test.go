@@ -0,0 +1,171 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTextSearch(t *testing.T) {
+	t.Parallel()
+	query := WebsearchToTSQuery("english", `"sad cat" or fat -rat`)
+
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "to_tsvector",
+			b:        ToTSVector("english", "title || ' ' || body"),
+			wantSQL:  "to_tsvector(?::regconfig, title || ' ' || body)",
+			wantArgs: []any{"english"},
+		},
+		{
+			name:    "to_tsvector_default_config",
+			b:       ToTSVector("", Expr("coalesce(?, '')", Ident("Title"))),
+			wantSQL: `to_tsvector(coalesce("Title", ''))`,
+		},
+		{
+			name:     "websearch_to_tsquery",
+			b:        query,
+			wantSQL:  "websearch_to_tsquery(?::regconfig, ?)",
+			wantArgs: []any{"english", `"sad cat" or fat -rat`},
+		},
+		{
+			name:     "plainto_tsquery",
+			b:        PlainToTSQuery("simple", "fat rats"),
+			wantSQL:  "plainto_tsquery(?::regconfig, ?)",
+			wantArgs: []any{"simple", "fat rats"},
+		},
+		{
+			name:     "phraseto_tsquery",
+			b:        PhraseToTSQuery("", "fat rats"),
+			wantSQL:  "phraseto_tsquery(?)",
+			wantArgs: []any{"fat rats"},
+		},
+		{
+			name:     "match",
+			b:        Match(ToTSVector("english", "body"), PlainToTSQuery("english", "cat")),
+			wantSQL:  "to_tsvector(?::regconfig, body) @@ plainto_tsquery(?::regconfig, ?)",
+			wantArgs: []any{"english", "english", "cat"},
+		},
+		{
+			name:     "rank",
+			b:        TSRank("search_vector", query),
+			wantSQL:  "ts_rank(search_vector, websearch_to_tsquery(?::regconfig, ?))",
+			wantArgs: []any{"english", `"sad cat" or fat -rat`},
+		},
+		{
+			name:    "rank_cd",
+			b:       TSRankCD("search_vector", "q"),
+			wantSQL: "ts_rank_cd(search_vector, q)",
+		},
+		{
+			name:     "headline",
+			b:        TSHeadline("english", "body", "q", ""),
+			wantSQL:  "ts_headline(?::regconfig, body, q)",
+			wantArgs: []any{"english"},
+		},
+		{
+			name:     "headline_options",
+			b:        TSHeadline("", "body", PlainToTSQuery("", "cat"), "StartSel=<b>, StopSel=</b>"),
+			wantSQL:  "ts_headline(body, plainto_tsquery(?), ?)",
+			wantArgs: []any{"cat", "StartSel=<b>, StopSel=</b>"},
+		},
+		{
+			name: "select",
+			b: Select("id").
+				Column(Alias{Expr: TSHeadline("english", "body", "q", "MaxWords=20"), As: "excerpt"}).
+				Column(Alias{Expr: TSRankCD("search_vector", "q"), As: "rank"}).
+				From("posts").
+				JoinClause(JoinTable(Expr("? AS q", query)).Cross()).
+				Where("published = ?", true).
+				Where(Match("search_vector", "q")).
+				OrderBy("rank DESC"),
+			wantSQL: "SELECT id, (ts_headline($1::regconfig, body, q, $2)) AS excerpt, (ts_rank_cd(search_vector, q)) AS rank " +
+				"FROM posts CROSS JOIN websearch_to_tsquery($3::regconfig, $4) AS q " +
+				"WHERE published = $5 AND search_vector @@ q ORDER BY rank DESC",
+			wantArgs: []any{"english", "MaxWords=20", "english", `"sad cat" or fat -rat`, true},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestTextSearchErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "to_tsvector_empty",
+			b:    ToTSVector("english", ""),
+			want: "to_tsvector arguments cannot be empty",
+		},
+		{
+			name: "to_tsvector_type",
+			b:    ToTSVector("english", 1),
+			want: "expected string or SQLizer, not int",
+		},
+		{
+			name: "match_empty",
+			b:    Match("search_vector", ""),
+			want: "text search matches must have a vector and a query",
+		},
+		{
+			name: "match_type",
+			b:    Match(1, "q"),
+			want: "expected string or SQLizer, not int",
+		},
+		{
+			name: "rank",
+			b:    TSRank("v", Expr("?", Named{"a": 1})),
+			want: "positional placeholders cannot be used with named parameters in \"?\"",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleMatch() {
+	query := WebsearchToTSQuery("english", "fat -rat")
+	sql, args, _ := Select("id", "title").
+		Column(Alias{Expr: TSRank("search_vector", query), As: "rank"}).
+		From("posts").
+		Where(Match("search_vector", query)).
+		OrderBy("rank DESC").
+		Limit(10).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, title, (ts_rank(search_vector, websearch_to_tsquery($1::regconfig, $2))) AS rank FROM posts WHERE search_vector @@ websearch_to_tsquery($3::regconfig, $4) ORDER BY rank DESC LIMIT 10
+	// [english fat -rat english fat -rat]
+}
@@ -3,6 +3,7 @@ package pgq
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 )
@@ -10,13 +11,18 @@ import (
 // UpdateBuilder builds SQL UPDATE statements.
 type UpdateBuilder struct {
 	prefixes   []SQLizer
+	ctes       []CTE
 	table      string
 	setClauses []setClause
 	fromParts  []SQLizer
 	whereParts []SQLizer
-	orderBys   []string
+	orderBys   []SQLizer
 	returning  []SQLizer
 	suffixes   []SQLizer
+
+	// err is the first error of a method, such as an invalid identifier,
+	// returned by SQL.
+	err error
 }
 
 type setClause struct {
@@ -24,6 +30,32 @@ type setClause struct {
 	value  any
 }
 
+// appendSetClauses writes the assignments of a SET clause.
+func appendSetClauses(clauses []setClause, w io.Writer, args []any) ([]any, error) {
+	setSQLs := make([]string, len(clauses))
+	for i, setClause := range clauses {
+		var valSQL string
+		if vs, ok := setClause.value.(SQLizer); ok {
+			vsql, vargs, err := nestedSQL(vs)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := vs.(SelectBuilder); ok {
+				valSQL = fmt.Sprintf("(%s)", vsql)
+			} else {
+				valSQL = vsql
+			}
+			args = append(args, vargs...)
+		} else {
+			valSQL = "?"
+			args = append(args, setClause.value)
+		}
+		setSQLs[i] = fmt.Sprintf("%s = %s", setClause.column, valSQL)
+	}
+	_, err := io.WriteString(w, strings.Join(setSQLs, ", "))
+	return args, err
+}
+
 func (b UpdateBuilder) SQL() (sqlStr string, args []any, err error) {
 	sqlStr, args, err = b.unfinalizedSQL()
 	if err != nil {
@@ -35,6 +67,10 @@ func (b UpdateBuilder) SQL() (sqlStr string, args []any, err error) {
 }
 
 func (b UpdateBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	if b.err != nil {
+		err = b.err
+		return
+	}
 	if b.table == "" {
 		err = fmt.Errorf("update statements must specify a table")
 		return
@@ -55,31 +91,19 @@ func (b UpdateBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 		sql.WriteString(" ")
 	}
 
+	args, err = appendCTEs(b.ctes, sql, args)
+	if err != nil {
+		return
+	}
+
 	sql.WriteString("UPDATE ")
 	sql.WriteString(b.table)
 
 	sql.WriteString(" SET ")
-	setSQLs := make([]string, len(b.setClauses))
-	for i, setClause := range b.setClauses {
-		var valSQL string
-		if vs, ok := setClause.value.(SQLizer); ok {
-			vsql, vargs, err := vs.SQL()
-			if err != nil {
-				return "", nil, err
-			}
-			if _, ok := vs.(SelectBuilder); ok {
-				valSQL = fmt.Sprintf("(%s)", vsql)
-			} else {
-				valSQL = vsql
-			}
-			args = append(args, vargs...)
-		} else {
-			valSQL = "?"
-			args = append(args, setClause.value)
-		}
-		setSQLs[i] = fmt.Sprintf("%s = %s", setClause.column, valSQL)
+	args, err = appendSetClauses(b.setClauses, sql, args)
+	if err != nil {
+		return
 	}
-	sql.WriteString(strings.Join(setSQLs, ", "))
 
 	if len(b.fromParts) > 0 {
 		sql.WriteString(" FROM ")
@@ -99,7 +123,10 @@ func (b UpdateBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
 
 	if len(b.orderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		args, err = appendSQL(b.orderBys, sql, ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.returning) > 0 {
@@ -143,18 +170,59 @@ func (b UpdateBuilder) PrefixExpr(expr SQLizer) UpdateBuilder {
 	return b
 }
 
+// With adds a common table expression to the WITH clause of the query.
+func (b UpdateBuilder) With(name string, expr SQLizer) UpdateBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr})
+}
+
+// WithRecursive adds a common table expression to the WITH clause of the query,
+// turning it into WITH RECURSIVE.
+func (b UpdateBuilder) WithRecursive(name string, expr SQLizer) UpdateBuilder {
+	return b.WithCTE(CTE{Name: name, Expr: expr, Recursive: true})
+}
+
+// WithCTE adds a common table expression to the WITH clause of the query.
+func (b UpdateBuilder) WithCTE(cte CTE) UpdateBuilder {
+	b.ctes = append(b.ctes, cte)
+	return b
+}
+
 // Table sets the table to be updated.
 func (b UpdateBuilder) Table(table string) UpdateBuilder {
 	b.table = table
 	return b
 }
 
+// TableIdent sets the table to be updated, quoted as an identifier.
+//
+// See QuoteIdent.
+func (b UpdateBuilder) TableIdent(table string) UpdateBuilder {
+	b = b.setErr(validateIdent(table))
+	return b.Table(QuoteIdent(table))
+}
+
 // Set adds SET clauses to the query.
 func (b UpdateBuilder) Set(column string, value any) UpdateBuilder {
 	b.setClauses = append(b.setClauses, setClause{column: column, value: value})
 	return b
 }
 
+// setErr keeps err to be returned by SQL, unless there is an earlier error.
+func (b UpdateBuilder) setErr(err error) UpdateBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// SetIdent adds a SET clause to the query, with column quoted as an identifier.
+//
+// See QuoteIdent.
+func (b UpdateBuilder) SetIdent(column string, value any) UpdateBuilder {
+	b = b.setErr(validateIdent(column))
+	return b.Set(QuoteIdent(column), value)
+}
+
 // SetMap is a convenience method which calls .Set for each key/value pair in clauses.
 func (b UpdateBuilder) SetMap(clauses map[string]any) UpdateBuilder {
 	keys := make([]string, len(clauses))
@@ -200,9 +268,17 @@ func (b UpdateBuilder) Where(pred any, args ...any) UpdateBuilder {
 	return b
 }
 
+// OrderByClause adds ORDER BY clause to the query.
+func (b UpdateBuilder) OrderByClause(pred any, args ...any) UpdateBuilder {
+	b.orderBys = append(b.orderBys, newPart(pred, args...))
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b UpdateBuilder) OrderBy(orderBys ...string) UpdateBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	for _, orderBy := range orderBys {
+		b = b.OrderByClause(orderBy)
+	}
 	return b
 }
 
@@ -10,19 +10,20 @@ func newWherePart(pred any, args ...any) SQLizer {
 	return &wherePart{pred: pred, args: args}
 }
 
-func (p wherePart) SQL() (sql string, args []any, err error) {
+func (p wherePart) SQL() (string, []any, error) {
+	return resolvedSQL(p)
+}
+
+func (p wherePart) unfinalizedSQL() (sql string, args []any, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
 		// no-op
-	case rawSQLizer:
-		return pred.unfinalizedSQL()
 	case SQLizer:
-		return pred.SQL()
+		return nestedSQL(pred)
 	case map[string]any:
-		return Eq(pred).SQL()
+		return nestedSQL(Eq(pred))
 	case string:
-		sql = pred
-		args = p.args
+		sql, args, err = namedSQL(pred, p.args)
 	default:
 		err = fmt.Errorf("expected string-keyed map or string, not %T", pred)
 	}
@@ -0,0 +1,363 @@
+package pgq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+const (
+	frameUnboundedPreceding = "UNBOUNDED PRECEDING"
+	frameUnboundedFollowing = "UNBOUNDED FOLLOWING"
+	frameCurrentRow         = "CURRENT ROW"
+	framePreceding          = "PRECEDING"
+	frameFollowing          = "FOLLOWING"
+)
+
+// FrameBound is the start or end of a window frame.
+//
+// See UnboundedPreceding, Preceding, CurrentRow, Following, and UnboundedFollowing.
+type FrameBound struct {
+	kind   string
+	offset any
+}
+
+// UnboundedPreceding returns the UNBOUNDED PRECEDING frame bound.
+func UnboundedPreceding() FrameBound {
+	return FrameBound{kind: frameUnboundedPreceding}
+}
+
+// UnboundedFollowing returns the UNBOUNDED FOLLOWING frame bound.
+func UnboundedFollowing() FrameBound {
+	return FrameBound{kind: frameUnboundedFollowing}
+}
+
+// CurrentRow returns the CURRENT ROW frame bound.
+func CurrentRow() FrameBound {
+	return FrameBound{kind: frameCurrentRow}
+}
+
+// Preceding returns the offset PRECEDING frame bound.
+// offset is bound as an arg, unless it is a SQLizer.
+//
+// With RANGE frames over dates or times, the offset must be an interval,
+// such as Expr("?::interval", "1 day").
+func Preceding(offset any) FrameBound {
+	return FrameBound{kind: framePreceding, offset: offset}
+}
+
+// Following returns the offset FOLLOWING frame bound.
+//
+// See Preceding.
+func Following(offset any) FrameBound {
+	return FrameBound{kind: frameFollowing, offset: offset}
+}
+
+// SQL returns the frame bound.
+func (f FrameBound) SQL() (string, []any, error) {
+	return resolvedSQL(f)
+}
+
+func (f FrameBound) unfinalizedSQL() (sql string, args []any, err error) {
+	switch f.kind {
+	case frameUnboundedPreceding, frameUnboundedFollowing, frameCurrentRow:
+		return f.kind, nil, nil
+	case framePreceding, frameFollowing:
+		if f.offset == nil {
+			err = fmt.Errorf("%s frame bound must have an offset", f.kind)
+			return
+		}
+		sql, args, err = nestedSQL(newValuePart(f.offset))
+		sql += " " + f.kind
+		return
+	}
+	err = errors.New("frame bounds must be created with UnboundedPreceding, Preceding, CurrentRow, Following, or UnboundedFollowing")
+	return
+}
+
+type windowFrame struct {
+	mode    string
+	start   FrameBound
+	end     *FrameBound
+	exclude string
+}
+
+// WindowBuilder builds a window definition for window functions and the
+// WINDOW clause of SelectBuilder.
+//
+// Ex:
+//
+//	Window().PartitionBy("department").OrderBy("salary DESC").RowsBetween(UnboundedPreceding(), CurrentRow())
+type WindowBuilder struct {
+	base        string
+	partitionBy []SQLizer
+	orderBy     []SQLizer
+	frame       windowFrame
+}
+
+// Window returns a new WindowBuilder.
+func Window() WindowBuilder {
+	return WindowBuilder{}
+}
+
+// SQL returns the window definition, without the surrounding parentheses.
+func (w WindowBuilder) SQL() (string, []any, error) {
+	return resolvedSQL(w)
+}
+
+func (w WindowBuilder) unfinalizedSQL() (sqlStr string, args []any, err error) {
+	sql := &bytes.Buffer{}
+	sep := ""
+
+	if w.base != "" {
+		sql.WriteString(w.base)
+		sep = " "
+	}
+
+	if len(w.partitionBy) > 0 {
+		sql.WriteString(sep + "PARTITION BY ")
+		args, err = appendSQL(w.partitionBy, sql, ", ", args)
+		if err != nil {
+			return
+		}
+		sep = " "
+	}
+
+	if len(w.orderBy) > 0 {
+		sql.WriteString(sep + "ORDER BY ")
+		args, err = appendSQL(w.orderBy, sql, ", ", args)
+		if err != nil {
+			return
+		}
+		sep = " "
+	}
+
+	if w.frame.mode != "" {
+		sql.WriteString(sep)
+		args, err = w.frame.appendToSQL(sql, args)
+		if err != nil {
+			return
+		}
+	} else if w.frame.exclude != "" {
+		err = errors.New("window frame exclusion requires a frame clause")
+		return
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+func (f windowFrame) appendToSQL(sql *bytes.Buffer, args []any) ([]any, error) {
+	if f.start.kind == frameUnboundedFollowing {
+		return nil, errors.New("window frame start cannot be UNBOUNDED FOLLOWING")
+	}
+	if f.end != nil && f.end.kind == frameUnboundedPreceding {
+		return nil, errors.New("window frame end cannot be UNBOUNDED PRECEDING")
+	}
+
+	sql.WriteString(f.mode)
+	sql.WriteString(" ")
+	var err error
+	if f.end == nil {
+		args, err = appendSQL([]SQLizer{f.start}, sql, "", args)
+	} else {
+		sql.WriteString("BETWEEN ")
+		args, err = appendSQL([]SQLizer{f.start, *f.end}, sql, " AND ", args)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.exclude != "" {
+		sql.WriteString(" EXCLUDE ")
+		sql.WriteString(f.exclude)
+	}
+	return args, nil
+}
+
+// Base sets the name of an existing window, defined in the WINDOW clause,
+// to copy the partitioning, ordering, and frame from.
+func (w WindowBuilder) Base(name string) WindowBuilder {
+	w.base = name
+	return w
+}
+
+// PartitionBy adds PARTITION BY expressions to the window.
+// Each expression can be a string or a SQLizer.
+func (w WindowBuilder) PartitionBy(exprs ...any) WindowBuilder {
+	for _, expr := range exprs {
+		w.partitionBy = append(w.partitionBy, newPart(expr))
+	}
+	return w
+}
+
+// OrderBy adds ORDER BY expressions to the window.
+// Each expression can be a string or a SQLizer.
+func (w WindowBuilder) OrderBy(exprs ...any) WindowBuilder {
+	for _, expr := range exprs {
+		w.orderBy = append(w.orderBy, newPart(expr))
+	}
+	return w
+}
+
+func (w WindowBuilder) setFrame(mode string, start FrameBound, end *FrameBound) WindowBuilder {
+	w.frame.mode = mode
+	w.frame.start = start
+	w.frame.end = end
+	return w
+}
+
+// Rows sets a ROWS frame starting at start and ending at the current row.
+func (w WindowBuilder) Rows(start FrameBound) WindowBuilder {
+	return w.setFrame("ROWS", start, nil)
+}
+
+// RowsBetween sets a ROWS BETWEEN start AND end frame.
+func (w WindowBuilder) RowsBetween(start, end FrameBound) WindowBuilder {
+	return w.setFrame("ROWS", start, &end)
+}
+
+// Range sets a RANGE frame starting at start and ending at the current row.
+func (w WindowBuilder) Range(start FrameBound) WindowBuilder {
+	return w.setFrame("RANGE", start, nil)
+}
+
+// RangeBetween sets a RANGE BETWEEN start AND end frame.
+func (w WindowBuilder) RangeBetween(start, end FrameBound) WindowBuilder {
+	return w.setFrame("RANGE", start, &end)
+}
+
+// Groups sets a GROUPS frame starting at start and ending at the current row.
+func (w WindowBuilder) Groups(start FrameBound) WindowBuilder {
+	return w.setFrame("GROUPS", start, nil)
+}
+
+// GroupsBetween sets a GROUPS BETWEEN start AND end frame.
+func (w WindowBuilder) GroupsBetween(start, end FrameBound) WindowBuilder {
+	return w.setFrame("GROUPS", start, &end)
+}
+
+// ExcludeCurrentRow excludes the current row from the frame.
+func (w WindowBuilder) ExcludeCurrentRow() WindowBuilder {
+	w.frame.exclude = "CURRENT ROW"
+	return w
+}
+
+// ExcludeGroup excludes the current row and its ordering peers from the frame.
+func (w WindowBuilder) ExcludeGroup() WindowBuilder {
+	w.frame.exclude = "GROUP"
+	return w
+}
+
+// ExcludeTies excludes the ordering peers of the current row from the frame.
+func (w WindowBuilder) ExcludeTies() WindowBuilder {
+	w.frame.exclude = "TIES"
+	return w
+}
+
+// ExcludeNoOthers explicitly excludes nothing from the frame, which is the default.
+func (w WindowBuilder) ExcludeNoOthers() WindowBuilder {
+	w.frame.exclude = "NO OTHERS"
+	return w
+}
+
+type over struct {
+	fn     SQLizer
+	window WindowBuilder
+	name   string
+}
+
+// Over calls the window function fn over the window.
+// fn can be a string or a SQLizer.
+//
+// Ex:
+//
+//	Select("name").Column(Over("rank()", Window().PartitionBy("department").OrderBy("salary DESC")))
+//	// SELECT name, rank() OVER (PARTITION BY department ORDER BY salary DESC)
+func Over(fn any, window WindowBuilder) SQLizer {
+	return over{fn: newPart(fn), window: window}
+}
+
+// OverWindow calls the window function fn over a window defined in the WINDOW
+// clause of the query.
+//
+// See SelectBuilder.Window.
+func OverWindow(fn any, name string) SQLizer {
+	return over{fn: newPart(fn), name: name}
+}
+
+func (o over) SQL() (string, []any, error) {
+	return resolvedSQL(o)
+}
+
+func (o over) unfinalizedSQL() (sql string, args []any, err error) {
+	sql, args, err = nestedSQL(o.fn)
+	if err != nil {
+		return
+	}
+	if sql == "" {
+		err = errors.New("window function calls must have a function")
+		return
+	}
+	if o.name != "" {
+		sql += " OVER " + o.name
+		return
+	}
+
+	wsql, wargs, err := nestedSQL(o.window)
+	if err != nil {
+		return
+	}
+	sql += " OVER (" + wsql + ")"
+	args = append(args, wargs...)
+	return
+}
+
+type namedWindow struct {
+	name   string
+	window WindowBuilder
+}
+
+func (w namedWindow) SQL() (string, []any, error) {
+	return resolvedSQL(w)
+}
+
+func (w namedWindow) unfinalizedSQL() (sql string, args []any, err error) {
+	sql, args, err = nestedSQL(w.window)
+	if err == nil {
+		sql = w.name + " AS (" + sql + ")"
+	}
+	return
+}
+
+func appendWindows(windows []namedWindow, sql *bytes.Buffer, args []any) ([]any, error) {
+	parts := make([]SQLizer, 0, len(windows))
+	names := make(map[string]bool, len(windows))
+	for _, w := range windows {
+		if w.name == "" {
+			return nil, errors.New("windows in the WINDOW clause must have a name")
+		}
+		if names[w.name] {
+			return nil, fmt.Errorf("window %q is already defined", w.name)
+		}
+		names[w.name] = true
+		parts = append(parts, w)
+	}
+	sql.WriteString(" WINDOW ")
+	return appendSQL(parts, sql, ", ", args)
+}
+
+// Window adds a named window to the WINDOW clause of the query, which window
+// functions can reference with OverWindow, or as the base of other windows.
+//
+// Ex:
+//
+//	Select("name").
+//		Column(OverWindow("rank()", "w")).
+//		Column(OverWindow("avg(salary)", "w")).
+//		From("employees").
+//		Window("w", Window().PartitionBy("department").OrderBy("salary DESC"))
+func (b SelectBuilder) Window(name string, window WindowBuilder) SelectBuilder {
+	b.windows = append(b.windows, namedWindow{name: name, window: window})
+	return b
+}
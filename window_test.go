@@ -0,0 +1,211 @@
+package pgq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		b        SQLizer
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "empty",
+			b:       Select().Column(Over("count(*)", Window())).From("t"),
+			wantSQL: "SELECT count(*) OVER () FROM t",
+		},
+		{
+			name:    "partition_order",
+			b:       Select("name").Column(Over("rank()", Window().PartitionBy("department").OrderBy("salary DESC", "id"))).From("employees"),
+			wantSQL: "SELECT name, rank() OVER (PARTITION BY department ORDER BY salary DESC, id) FROM employees",
+		},
+		{
+			name: "rows_between",
+			b: Select("id").
+				Column(Over(Expr("sum(amount) FILTER (WHERE kind = ?)", "sale"), Window().OrderBy("id").RowsBetween(Preceding(3), CurrentRow()))).
+				From("orders").
+				Where("customer_id = ?", 7),
+			wantSQL:  "SELECT id, sum(amount) FILTER (WHERE kind = $1) OVER (ORDER BY id ROWS BETWEEN $2 PRECEDING AND CURRENT ROW) FROM orders WHERE customer_id = $3",
+			wantArgs: []any{"sale", 3, 7},
+		},
+		{
+			name:     "rows",
+			b:        Over("avg(x)", Window().OrderBy("id").Rows(Preceding(2))),
+			wantSQL:  "avg(x) OVER (ORDER BY id ROWS ? PRECEDING)",
+			wantArgs: []any{2},
+		},
+		{
+			name:     "range_interval",
+			b:        Over("sum(x)", Window().OrderBy("created_at").RangeBetween(Preceding(Expr("?::interval", "1 day")), Following(Expr("?::interval", "1 hour")))),
+			wantSQL:  "sum(x) OVER (ORDER BY created_at RANGE BETWEEN ?::interval PRECEDING AND ?::interval FOLLOWING)",
+			wantArgs: []any{"1 day", "1 hour"},
+		},
+		{
+			name:    "range_unbounded",
+			b:       Over("last_value(x)", Window().OrderBy("id").RangeBetween(UnboundedPreceding(), UnboundedFollowing())),
+			wantSQL: "last_value(x) OVER (ORDER BY id RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)",
+		},
+		{
+			name:    "range",
+			b:       Over("sum(x)", Window().OrderBy("id").Range(UnboundedPreceding())),
+			wantSQL: "sum(x) OVER (ORDER BY id RANGE UNBOUNDED PRECEDING)",
+		},
+		{
+			name:     "groups_exclude",
+			b:        Over("count(*)", Window().OrderBy("score").GroupsBetween(Preceding(1), Following(1)).ExcludeTies()),
+			wantSQL:  "count(*) OVER (ORDER BY score GROUPS BETWEEN ? PRECEDING AND ? FOLLOWING EXCLUDE TIES)",
+			wantArgs: []any{1, 1},
+		},
+		{
+			name:    "groups",
+			b:       Over("count(*)", Window().OrderBy("score").Groups(CurrentRow()).ExcludeCurrentRow()),
+			wantSQL: "count(*) OVER (ORDER BY score GROUPS CURRENT ROW EXCLUDE CURRENT ROW)",
+		},
+		{
+			name:    "exclude",
+			b:       Over("count(*)", Window().RowsBetween(UnboundedPreceding(), CurrentRow()).ExcludeGroup().ExcludeNoOthers()),
+			wantSQL: "count(*) OVER (ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW EXCLUDE NO OTHERS)",
+		},
+		{
+			name: "named_windows",
+			b: Select("name").
+				Column(OverWindow("rank()", "w")).
+				Column(Over("sum(salary)", Window().Base("w").RowsBetween(Preceding(1), Following(1)))).
+				From("employees").
+				Where("active = ?", true).
+				GroupBy("name", "department", "salary").
+				Having("count(*) > ?", 0).
+				Window("w", Window().PartitionBy("department").OrderBy("salary DESC")).
+				Window("w2", Window().Base("w").Rows(UnboundedPreceding())).
+				OrderBy("name").
+				Limit(10),
+			wantSQL: "SELECT name, rank() OVER w, sum(salary) OVER (w ROWS BETWEEN $1 PRECEDING AND $2 FOLLOWING) " +
+				"FROM employees WHERE active = $3 GROUP BY name, department, salary HAVING count(*) > $4 " +
+				"WINDOW w AS (PARTITION BY department ORDER BY salary DESC), w2 AS (w ROWS UNBOUNDED PRECEDING) " +
+				"ORDER BY name LIMIT 10",
+			wantArgs: []any{1, 1, true, 0},
+		},
+		{
+			name: "window_args",
+			b: Select().Column(OverWindow("avg(x)", "w")).
+				From("t").
+				Window("w", Window().PartitionBy(Expr("x > ?", 5)).OrderBy("id").Rows(Preceding(2))).
+				Where("y = ?", 1),
+			wantSQL:  "SELECT avg(x) OVER w FROM t WHERE y = $1 WINDOW w AS (PARTITION BY x > $2 ORDER BY id ROWS $3 PRECEDING)",
+			wantArgs: []any{1, 5, 2},
+		},
+		{
+			name: "union",
+			b: Select("id").Column(OverWindow("row_number()", "w")).From("a").Window("w", Window().OrderBy("id")).
+				Union(Select("id", "0").From("b")),
+			wantSQL: "SELECT id, row_number() OVER w FROM a WINDOW w AS (ORDER BY id) UNION SELECT id, 0 FROM b",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := tc.b.SQL()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("expected SQL to be %q, got %q instead", tc.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("wanted %v, got %v instead", tc.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestWindowErr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		b    SQLizer
+		want string
+	}{
+		{
+			name: "exclude_without_frame",
+			b:    Over("count(*)", Window().OrderBy("id").ExcludeTies()),
+			want: "window frame exclusion requires a frame clause",
+		},
+		{
+			name: "start_unbounded_following",
+			b:    Over("count(*)", Window().RowsBetween(UnboundedFollowing(), CurrentRow())),
+			want: "window frame start cannot be UNBOUNDED FOLLOWING",
+		},
+		{
+			name: "end_unbounded_preceding",
+			b:    Over("count(*)", Window().RowsBetween(CurrentRow(), UnboundedPreceding())),
+			want: "window frame end cannot be UNBOUNDED PRECEDING",
+		},
+		{
+			name: "missing_offset",
+			b:    Over("count(*)", Window().Rows(Preceding(nil))),
+			want: "PRECEDING frame bound must have an offset",
+		},
+		{
+			name: "zero_frame_bound",
+			b:    Over("count(*)", Window().RowsBetween(FrameBound{}, CurrentRow())),
+			want: "frame bounds must be created with UnboundedPreceding, Preceding, CurrentRow, Following, or UnboundedFollowing",
+		},
+		{
+			name: "missing_function",
+			b:    OverWindow("", "w"),
+			want: "window function calls must have a function",
+		},
+		{
+			name: "unnamed_window",
+			b:    Select("a").From("t").Window("", Window()),
+			want: "windows in the WINDOW clause must have a name",
+		},
+		{
+			name: "duplicate_window",
+			b:    Select("a").From("t").Window("w", Window()).Window("w", Window().OrderBy("a")),
+			want: `window "w" is already defined`,
+		},
+		{
+			name: "window_error",
+			b:    Select("a").From("t").Window("w", Window().ExcludeGroup()),
+			want: "window frame exclusion requires a frame clause",
+		},
+		{
+			name: "set_operation",
+			b:    Select("a").Union(Select("b")).Window("w", Window()),
+			want: "set operations cannot have result columns, FROM, WHERE, GROUP BY, HAVING, or WINDOW clauses; use a subquery instead",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tc.b.SQL()
+			if err == nil || err.Error() != tc.want {
+				t.Errorf("expected error to be %q, got %v instead", tc.want, err)
+			}
+		})
+	}
+}
+
+func ExampleOver() {
+	sql, args, _ := Select("id", "amount").
+		Column(Over("sum(amount)", Window().PartitionBy("customer_id").OrderBy("id").RowsBetween(Preceding(2), CurrentRow()))).
+		Column(OverWindow("rank()", "w")).
+		From("orders").
+		Where("status = ?", "paid").
+		Window("w", Window().PartitionBy("customer_id").OrderBy("amount DESC")).
+		SQL()
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, amount, sum(amount) OVER (PARTITION BY customer_id ORDER BY id ROWS BETWEEN $1 PRECEDING AND CURRENT ROW), rank() OVER w FROM orders WHERE status = $2 WINDOW w AS (PARTITION BY customer_id ORDER BY amount DESC)
+	// [2 paid]
+}